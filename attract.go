@@ -0,0 +1,230 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/akshayxml/spaders/bot"
+	"github.com/akshayxml/spaders/models/AttractPage"
+	"github.com/akshayxml/spaders/models/EnemyType"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/sprites"
+	"github.com/akshayxml/spaders/theme"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	// How long the menu has to sit untouched before the attract loop starts.
+	attractDelay     = 10 * simulation.TicksPerSecond
+	attractPageTicks = 6 * simulation.TicksPerSecond
+	// The demo game is cut short if the autopilot is still going by then.
+	demoTicks = 30 * simulation.TicksPerSecond
+)
+
+var attractPages = []AttractPage.AttractPage{AttractPage.Logo, AttractPage.ScoreTable, AttractPage.HighScores, AttractPage.Demo}
+
+var scoreTableEnemies = []EnemyType.EnemyType{EnemyType.Three, EnemyType.Two, EnemyType.One}
+
+// attractState cycles the title screen through its pages like an arcade
+// cabinet waiting for a coin. Any input brings the menu back.
+type attractState struct {
+	active    bool
+	idle      int
+	page      int
+	pageTicks int
+	demo      *simulation.World
+	autopilot bot.Autopilot
+}
+
+// updateAttract runs the attract loop and reports whether it used up this
+// tick's input, in which case the menu should ignore it.
+func (g *Game) updateAttract() bool {
+	var a = &g.attract
+	g.pollHighScores()
+	if g.actions.IsAnyJustPressed() {
+		a.idle = 0
+		if a.active {
+			g.stopAttract()
+			return true
+		}
+		return false
+	}
+	if !a.active {
+		a.idle++
+		if a.idle >= attractDelay {
+			a.active = true
+			g.fetchHighScores()
+			g.showAttractPage(1)
+		}
+		return false
+	}
+
+	a.pageTicks++
+	if a.demo != nil {
+		a.demo.Step([]simulation.Input{a.autopilot.Input(a.demo)})
+		g.events.PublishAll(a.demo.Events)
+		if !a.demo.CanPlay() || a.pageTicks >= demoTicks {
+			g.showAttractPage(a.page + 1)
+		}
+	} else if a.pageTicks >= attractPageTicks {
+		g.showAttractPage(a.page + 1)
+	}
+	return true
+}
+
+func (g *Game) showAttractPage(page int) {
+	var a = &g.attract
+	g.stopDemo()
+	a.page = page % len(attractPages)
+	a.pageTicks = 0
+	if attractPages[a.page] == AttractPage.Demo {
+		a.demo = simulation.New(time.Now().UnixNano(), g.difficulty, 1)
+		a.autopilot = bot.Autopilot{}
+		g.world = a.demo
+	}
+}
+
+// stopDemo puts back the world the menu was about to start.
+func (g *Game) stopDemo() {
+	if g.attract.demo != nil {
+		g.attract.demo = nil
+		g.world = g.turns[g.currentTurn]
+	}
+}
+
+func (g *Game) stopAttract() {
+	g.stopDemo()
+	g.attract.active = false
+	g.attract.page = 0
+	g.attract.pageTicks = 0
+	g.attract.idle = 0
+}
+
+func (g *Game) DrawTitle(screen *ebiten.Image, palette theme.Palette) {
+	if !g.attract.active {
+		g.DrawMenu(screen, palette)
+		return
+	}
+	switch attractPages[g.attract.page] {
+	case AttractPage.Logo:
+		g.DrawMenu(screen, palette)
+	case AttractPage.ScoreTable:
+		g.drawScoreTable(screen, palette)
+	case AttractPage.HighScores:
+		g.drawHighScores(screen, palette)
+	case AttractPage.Demo:
+		g.renderWorld(screen, palette)
+		g.drawDemoBanner(screen, palette)
+	}
+}
+
+func drawAttractTitle(screen *ebiten.Image, palette theme.Palette, subtitle string) {
+	face := &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   bigFontSize,
+	}
+	textOp := &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), 100)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	textOp.PrimaryAlign = text.AlignCenter
+	textOp.SecondaryAlign = text.AlignCenter
+	text.Draw(screen, "SPADERS", face, textOp)
+
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), 140)
+	textOp.ColorScale.ScaleWithColor(palette.Text)
+	textOp.PrimaryAlign = text.AlignCenter
+	text.Draw(screen, subtitle, &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   normalFontSize,
+	}, textOp)
+}
+
+func (g *Game) drawScoreTable(screen *ebiten.Image, palette theme.Palette) {
+	drawAttractTitle(screen, palette, "SCORE ADVANCE TABLE")
+	face := &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   normalFontSize,
+	}
+	var y float64 = 200
+	for _, enemyType := range scoreTableEnemies {
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Translate(240, y)
+		opts.ColorScale.ScaleWithColor(palette.Enemy)
+		screen.DrawImage(enemyImages[enemyType], opts)
+
+		textOp := &text.DrawOptions{}
+		textOp.GeoM.Translate(310, y+8)
+		textOp.ColorScale.ScaleWithColor(palette.Accent)
+		text.Draw(screen, "= "+strconv.Itoa(simulation.EnemyPoints[enemyType])+" POINTS", face, textOp)
+		y += 50
+	}
+
+	for _, rect := range sprites.GetEnemyBulletRectangles() {
+		ebitenutil.DrawRect(screen, rect.Position.X+258, rect.Position.Y+y+4, rect.Width, rect.Height, palette.EnemyBullet)
+	}
+	textOp := &text.DrawOptions{}
+	textOp.GeoM.Translate(310, y+8)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	text.Draw(screen, "= "+strconv.Itoa(simulation.BulletPoints)+" POINTS", face, textOp)
+}
+
+func (g *Game) drawHighScores(screen *ebiten.Image, palette theme.Palette) {
+	var title, entries = g.highScoreTable()
+	drawAttractTitle(screen, palette, title)
+	face := &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   normalFontSize,
+	}
+	if len(entries) == 0 {
+		textOp := &text.DrawOptions{}
+		textOp.GeoM.Translate(float64(logicalWidth/2), 220)
+		textOp.ColorScale.ScaleWithColor(palette.Accent)
+		textOp.PrimaryAlign = text.AlignCenter
+		text.Draw(screen, "NO SCORES YET", face, textOp)
+		return
+	}
+	for i, entry := range entries {
+		var y = 190 + float64(24*i)
+
+		textOp := &text.DrawOptions{}
+		textOp.GeoM.Translate(200, y)
+		textOp.ColorScale.ScaleWithColor(palette.Text)
+		textOp.PrimaryAlign = text.AlignEnd
+		text.Draw(screen, strconv.Itoa(i+1)+".", face, textOp)
+
+		textOp = &text.DrawOptions{}
+		textOp.GeoM.Translate(220, y)
+		textOp.ColorScale.ScaleWithColor(palette.Accent)
+		text.Draw(screen, entry.Name, face, textOp)
+
+		textOp = &text.DrawOptions{}
+		textOp.GeoM.Translate(440, y)
+		textOp.ColorScale.ScaleWithColor(palette.Text)
+		textOp.PrimaryAlign = text.AlignEnd
+		text.Draw(screen, strconv.Itoa(entry.Score), face, textOp)
+	}
+}
+
+func (g *Game) drawDemoBanner(screen *ebiten.Image, palette theme.Palette) {
+	face := &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   normalFontSize,
+	}
+	textOp := &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), 40)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	textOp.PrimaryAlign = text.AlignCenter
+	text.Draw(screen, "DEMO", face, textOp)
+
+	// Blink the prompt about once a second.
+	if g.attract.pageTicks/(simulation.TicksPerSecond/2)%2 == 0 {
+		textOp = &text.DrawOptions{}
+		textOp.GeoM.Translate(float64(logicalWidth/2), float64(logicalHeight/2))
+		textOp.ColorScale.ScaleWithColor(palette.Text)
+		textOp.PrimaryAlign = text.AlignCenter
+		text.Draw(screen, "PRESS ANY KEY", face, textOp)
+	}
+}
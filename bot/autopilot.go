@@ -0,0 +1,191 @@
+package bot
+
+import (
+	"math"
+
+	"github.com/akshayxml/spaders/models"
+	"github.com/akshayxml/spaders/models/EntityState"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/sprites"
+)
+
+const (
+	// How many ticks ahead incoming bullets are considered.
+	horizon = 60
+	// Extra room kept between the cannon and a bullet passing by.
+	dodgeMargin = 4
+	// Bullets that would land under a bunker are not worth firing.
+	bunkerPenalty = 1000
+)
+
+// Plans are held for one of these many ticks before standing still.
+var planLengths = []int{2, 4, 8, 12, 16, 24, 32, 48}
+
+// Autopilot plays one cannon of a world. It produces the same inputs a
+// player would, so it can stand in for the keyboard anywhere.
+type Autopilot struct {
+	Player int
+}
+
+var cannonWidth, cannonHeight = spriteSize(sprites.GetPlayerRectangles())
+
+func spriteSize(rects []models.Rectangle) (float64, float64) {
+	var width, height float64
+	for _, rect := range rects {
+		width = max(width, rect.Position.X+rect.Width)
+		height = max(height, rect.Position.Y+rect.Height)
+	}
+	return width, height
+}
+
+// Input decides what the cannon does this tick.
+func (a *Autopilot) Input(w *simulation.World) simulation.Input {
+	if a.Player >= len(w.Players) {
+		return 0
+	}
+	var cannon = w.Players[a.Player]
+	if cannon.Lives == 0 {
+		return 0
+	}
+
+	var target, aimed = a.target(w, cannon)
+	var in = a.move(threats(w), cannon, target)
+	if aimed && !cannon.Bullet.IsActive && math.Abs(cannon.Position.X-target) <= 2*cannon.Speed && !underBunker(w, cannon.Position.X) {
+		in |= simulation.InputFire
+	}
+	return in
+}
+
+// target returns where the cannon should be to hit the lowest invaders,
+// leading them by the time a bullet takes to get there. Without invaders it
+// stays where it is.
+func (a *Autopilot) target(w *simulation.World, cannon *models.Player) (float64, bool) {
+	var best, bestCost = cannon.Position.X, math.Inf(1)
+	var lowest = math.Inf(-1)
+	for _, enemy := range w.Enemies {
+		if enemy.State == EntityState.Alive {
+			lowest = max(lowest, enemy.Position.Y)
+		}
+	}
+
+	for _, enemy := range w.Enemies {
+		if enemy.State != EntityState.Alive {
+			continue
+		}
+		var bottom = enemy.Position.Y + enemy.GetEnemyHeight()
+		var ticks = (cannon.Position.Y - bottom) / float64(cannon.Bullet.Speed)
+		var centre = enemy.Position.X + enemy.GetEnemyWidth()/2 +
+			w.EnemyState.HorizontalSpeed*float64(w.EnemyState.HorizontalDirection)*ticks
+		var x = clampCannon(centre - cannonWidth/2)
+
+		// Lower rows come first, then whatever is closest.
+		var cost = (lowest-enemy.Position.Y)*10 + math.Abs(x-cannon.Position.X)
+		if underBunker(w, x) {
+			cost += bunkerPenalty
+		}
+		if cost < bestCost {
+			best, bestCost = x, cost
+		}
+	}
+	return best, !math.IsInf(bestCost, 1)
+}
+
+// move picks the first input of the plan that keeps the cannon out of the
+// way of every bullet for longest, preferring plans that end near target.
+func (a *Autopilot) move(threats []models.Bullet, cannon *models.Player, target float64) simulation.Input {
+	var bestInput simulation.Input
+	var bestHit, bestDistance = -1, math.Inf(1)
+	var consider = func(direction float64, length int) {
+		var hit, end = a.firstHit(threats, cannon, direction, length)
+		var distance = math.Abs(end - target)
+		if hit > bestHit || (hit == bestHit && distance < bestDistance) {
+			bestHit, bestDistance = hit, distance
+			switch {
+			case direction < 0:
+				bestInput = simulation.InputLeft
+			case direction > 0:
+				bestInput = simulation.InputRight
+			default:
+				bestInput = 0
+			}
+		}
+	}
+
+	consider(0, 0)
+	for _, length := range planLengths {
+		consider(-1, length)
+		consider(1, length)
+	}
+	return bestInput
+}
+
+// threats returns the enemy bullets that no bunker is going to stop.
+func threats(w *simulation.World) []models.Bullet {
+	var bullets []models.Bullet
+	for _, bullet := range w.EnemyBullets.Values() {
+		if bullet.IsActive && !blockedByBunker(w, bullet) {
+			bullets = append(bullets, bullet)
+		}
+	}
+	return bullets
+}
+
+// firstHit follows the cannon moving in direction for length ticks and then
+// standing still, and returns the first tick a bullet would hit it, or
+// horizon if none does, along with where the cannon ends up.
+func (a *Autopilot) firstHit(threats []models.Bullet, cannon *models.Player, direction float64, length int) (int, float64) {
+	var x = cannon.Position.X
+	for tick := 1; tick < horizon; tick++ {
+		if tick <= length {
+			x = step(x, direction, cannon.Speed)
+		}
+		for _, bullet := range threats {
+			var bottom = bullet.Position.Y + bullet.Height + float64(bullet.Speed*bullet.Direction*tick)
+			if bottom >= cannon.Position.Y && bottom <= cannon.Position.Y+cannonHeight &&
+				bullet.Position.X >= x-dodgeMargin && bullet.Position.X <= x+cannonWidth+dodgeMargin {
+				return tick, x
+			}
+		}
+	}
+	for tick := horizon; tick <= length; tick++ {
+		x = step(x, direction, cannon.Speed)
+	}
+	return horizon, x
+}
+
+// step moves x the way models.Player does.
+func step(x, direction, speed float64) float64 {
+	if direction < 0 && x >= simulation.LeftBoundary {
+		return x - speed
+	}
+	if direction > 0 && x <= simulation.RightBoundary {
+		return x + speed
+	}
+	return x
+}
+
+func clampCannon(x float64) float64 {
+	return min(max(x, simulation.LeftBoundary), simulation.RightBoundary)
+}
+
+// underBunker reports whether a bullet fired by a cannon at x would hit a
+// bunker first.
+func underBunker(w *simulation.World, x float64) bool {
+	var bulletX = x + cannonWidth/2
+	for _, sprite := range w.BunkerSprites {
+		if sprite.Height > 0 && bulletX >= sprite.Position.X && bulletX <= sprite.Position.X+sprite.Width {
+			return true
+		}
+	}
+	return false
+}
+
+func blockedByBunker(w *simulation.World, bullet models.Bullet) bool {
+	for _, sprite := range w.BunkerSprites {
+		if sprite.Height > 0 && bullet.Position.X >= sprite.Position.X && bullet.Position.X <= sprite.Position.X+sprite.Width &&
+			sprite.Position.Y+sprite.Height >= bullet.Position.Y+bullet.Height {
+			return true
+		}
+	}
+	return false
+}
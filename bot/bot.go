@@ -0,0 +1,35 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/akshayxml/spaders/simulation"
+)
+
+// Bot plays one cannon of a world, one tick at a time.
+type Bot interface {
+	Input(w *simulation.World) simulation.Input
+}
+
+var Names = []string{"autopilot", "random", "idle"}
+
+// New returns the bot called name playing the given cannon. Bots that make
+// random choices make the same ones for the same seed.
+func New(name string, player int, seed int64) (Bot, error) {
+	switch name {
+	case "autopilot":
+		return &Autopilot{Player: player}, nil
+	case "random":
+		return NewRandom(seed), nil
+	case "idle":
+		return idle{}, nil
+	}
+	return nil, fmt.Errorf("bot: unknown bot %q", name)
+}
+
+// idle never does anything, which shows how long the invaders take to land.
+type idle struct{}
+
+func (idle) Input(w *simulation.World) simulation.Input {
+	return 0
+}
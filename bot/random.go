@@ -0,0 +1,32 @@
+package bot
+
+import (
+	"math/rand"
+
+	"github.com/akshayxml/spaders/simulation"
+)
+
+// Longest a Random bot keeps going the same way, in ticks.
+const maxRandomHold = 60
+
+// Random mashes buttons: it picks a direction, holds it for a random number
+// of ticks and fires whenever it can. It is the baseline other bots should
+// beat.
+type Random struct {
+	rand  *rand.Rand
+	input simulation.Input
+	hold  int
+}
+
+func NewRandom(seed int64) *Random {
+	return &Random{rand: rand.New(rand.NewSource(seed))}
+}
+
+func (r *Random) Input(w *simulation.World) simulation.Input {
+	if r.hold == 0 {
+		r.input = []simulation.Input{0, simulation.InputLeft, simulation.InputRight}[r.rand.Intn(3)]
+		r.hold = 1 + r.rand.Intn(maxRandomHold)
+	}
+	r.hold--
+	return r.input | simulation.InputFire
+}
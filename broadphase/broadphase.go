@@ -0,0 +1,58 @@
+// Package broadphase finds which boxes might touch a given box without
+// testing every one of them. Callers still do their exact test on what it
+// returns.
+package broadphase
+
+import "sort"
+
+// AABB is an axis aligned box. Boxes that only share an edge overlap, and a
+// box with no size stands for a point.
+type AABB struct {
+	Left, Top, Right, Bottom float64
+}
+
+func (a AABB) Overlaps(b AABB) bool {
+	return a.Left <= b.Right && b.Left <= a.Right && a.Top <= b.Bottom && b.Top <= a.Bottom
+}
+
+// Index holds boxes under ids chosen by the caller, usually their position
+// in a slice. It is refilled every tick.
+type Index interface {
+	Clear()
+	Insert(id int, box AABB)
+	// Query appends to dst the ids of the boxes that overlap box, each once
+	// and in increasing order so that callers stay deterministic.
+	Query(box AABB, dst []int) []int
+}
+
+// List tests every box. It is what Grid is measured against and is fine
+// for a handful of boxes.
+type List struct {
+	ids   []int
+	boxes []AABB
+}
+
+func NewList() *List {
+	return &List{}
+}
+
+func (l *List) Clear() {
+	l.ids = l.ids[:0]
+	l.boxes = l.boxes[:0]
+}
+
+func (l *List) Insert(id int, box AABB) {
+	l.ids = append(l.ids, id)
+	l.boxes = append(l.boxes, box)
+}
+
+func (l *List) Query(box AABB, dst []int) []int {
+	var start = len(dst)
+	for i, other := range l.boxes {
+		if other.Overlaps(box) {
+			dst = append(dst, l.ids[i])
+		}
+	}
+	sort.Ints(dst[start:])
+	return dst
+}
@@ -0,0 +1,88 @@
+package broadphase
+
+import (
+	"math"
+	"sort"
+)
+
+// Grid buckets boxes into square cells covering an area, so a query only
+// looks at the boxes in the cells it touches. Boxes reaching outside the
+// area are kept in the cells along its edge.
+type Grid struct {
+	cellSize   float64
+	cols, rows int
+	cells      [][]int
+	boxes      []AABB
+	// Ids seen by the current query carry its stamp, so that a box spanning
+	// several cells is returned once.
+	stamps []uint32
+	stamp  uint32
+}
+
+func NewGrid(width, height, cellSize float64) *Grid {
+	var cols = max(int(math.Ceil(width/cellSize)), 1)
+	var rows = max(int(math.Ceil(height/cellSize)), 1)
+	return &Grid{
+		cellSize: cellSize,
+		cols:     cols,
+		rows:     rows,
+		cells:    make([][]int, cols*rows),
+	}
+}
+
+func (g *Grid) Clear() {
+	for i := range g.cells {
+		g.cells[i] = g.cells[i][:0]
+	}
+}
+
+// span returns the range of cells box covers, clamped to the grid.
+func (g *Grid) span(box AABB) (col0, row0, col1, row1 int) {
+	var clampCol = func(x float64) int {
+		return min(max(int(math.Floor(x/g.cellSize)), 0), g.cols-1)
+	}
+	var clampRow = func(y float64) int {
+		return min(max(int(math.Floor(y/g.cellSize)), 0), g.rows-1)
+	}
+	return clampCol(box.Left), clampRow(box.Top), clampCol(box.Right), clampRow(box.Bottom)
+}
+
+func (g *Grid) Insert(id int, box AABB) {
+	for id >= len(g.boxes) {
+		g.boxes = append(g.boxes, AABB{})
+		g.stamps = append(g.stamps, 0)
+	}
+	g.boxes[id] = box
+	var col0, row0, col1, row1 = g.span(box)
+	for row := row0; row <= row1; row++ {
+		for col := col0; col <= col1; col++ {
+			g.cells[row*g.cols+col] = append(g.cells[row*g.cols+col], id)
+		}
+	}
+}
+
+func (g *Grid) Query(box AABB, dst []int) []int {
+	g.stamp++
+	if g.stamp == 0 {
+		for i := range g.stamps {
+			g.stamps[i] = 0
+		}
+		g.stamp = 1
+	}
+	var start = len(dst)
+	var col0, row0, col1, row1 = g.span(box)
+	for row := row0; row <= row1; row++ {
+		for col := col0; col <= col1; col++ {
+			for _, id := range g.cells[row*g.cols+col] {
+				if g.stamps[id] != g.stamp {
+					g.stamps[id] = g.stamp
+					if g.boxes[id].Overlaps(box) {
+						dst = append(dst, id)
+					}
+				}
+			}
+		}
+	}
+	sort.Ints(dst[start:])
+	return dst
+}
@@ -0,0 +1,95 @@
+package broadphase
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// The area the game plays in, and the cell size it uses.
+const width, height, cellSize = 800, 600, 32
+
+func randomBox(rng *rand.Rand, maxSize float64) AABB {
+	// Some boxes start off the area, which the grid keeps along its edges.
+	var x, y = rng.Float64()*(width+100) - 50, rng.Float64()*(height+100) - 50
+	return AABB{Left: x, Top: y, Right: x + rng.Float64()*maxSize, Bottom: y + rng.Float64()*maxSize}
+}
+
+func TestGridMatchesList(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+	var grid, list = NewGrid(width, height, cellSize), NewList()
+	for round := 0; round < 20; round++ {
+		grid.Clear()
+		list.Clear()
+		for id := 0; id < 200; id++ {
+			// Every few ids are left out, as for dead invaders.
+			if id%7 == 3 {
+				continue
+			}
+			var box = randomBox(rng, 3*cellSize)
+			grid.Insert(id, box)
+			list.Insert(id, box)
+		}
+		for i := 0; i < 500; i++ {
+			var box = randomBox(rng, 2*cellSize)
+			if i%2 == 0 {
+				box.Right, box.Bottom = box.Left, box.Top
+			}
+			var got, want = grid.Query(box, nil), list.Query(box, nil)
+			if !slices.Equal(got, want) {
+				t.Fatalf("round %d: Query(%+v) = %v, want %v", round, box, got, want)
+			}
+		}
+	}
+}
+
+func TestQueryAppends(t *testing.T) {
+	var grid = NewGrid(width, height, cellSize)
+	grid.Insert(4, AABB{Left: 10, Top: 10, Right: 100, Bottom: 100})
+	grid.Insert(2, AABB{Left: 50, Top: 50, Right: 60, Bottom: 60})
+	var got = grid.Query(AABB{Left: 55, Top: 55, Right: 55, Bottom: 55}, []int{9})
+	if !slices.Equal(got, []int{9, 2, 4}) {
+		t.Errorf("Query = %v, want [9 2 4]", got)
+	}
+}
+
+// A collision pass indexes every bunker and invader and looks up every
+// bullet, with many more of both than a normal game has.
+func benchmarkIndex(b *testing.B, newIndex func() Index) {
+	for _, boxCount := range []int{50, 500} {
+		for _, bulletCount := range []int{50, 200, 1000} {
+			b.Run(fmt.Sprintf("boxes=%d/bullets=%d", boxCount, bulletCount), func(b *testing.B) {
+				var rng = rand.New(rand.NewSource(1))
+				var boxes, bullets = make([]AABB, boxCount), make([]AABB, bulletCount)
+				for i := range boxes {
+					boxes[i] = randomBox(rng, 20)
+				}
+				for i := range bullets {
+					bullets[i] = randomBox(rng, 0)
+				}
+				var index = newIndex()
+				var found []int
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					index.Clear()
+					for id, box := range boxes {
+						index.Insert(id, box)
+					}
+					for _, bullet := range bullets {
+						found = index.Query(bullet, found[:0])
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkGrid(b *testing.B) {
+	benchmarkIndex(b, func() Index { return NewGrid(width, height, cellSize) })
+}
+
+func BenchmarkList(b *testing.B) {
+	benchmarkIndex(b, func() Index { return NewList() })
+}
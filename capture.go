@@ -0,0 +1,149 @@
+package main
+
+import (
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/akshayxml/spaders/input"
+	"github.com/akshayxml/spaders/models/EnemyType"
+	"github.com/akshayxml/spaders/models/Screen"
+	"github.com/akshayxml/spaders/render"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/theme"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// How far back a recording goes.
+	recordingSeconds = 10
+	captureName      = "spaders-20060102-150405"
+)
+
+// The pictures and font for drawing recordings, read the first time one is
+// saved.
+var loadRenderAssets = sync.OnceValues(func() (*render.Assets, error) {
+	return render.LoadAssets(bgImgLocation, map[EnemyType.EnemyType]string{
+		EnemyType.One:   enemyImg1Location,
+		EnemyType.Two:   enemyImg2Location,
+		EnemyType.Three: enemyImg3Location,
+	}, fontLocation)
+})
+
+// captureState keeps the last few seconds of the game for recordings. Only
+// the worlds are kept, a frame of GIF every few ticks, and drawn when the
+// recording is saved, which is far smaller than keeping the pictures.
+type captureState struct {
+	frames []*simulation.World
+	next   int
+	count  int
+	world  *simulation.World
+	tick   int64
+	// Set when a screenshot is asked for, and taken on the next Draw.
+	screenshot bool
+}
+
+func (g *Game) updateCapture() {
+	var c = &g.capture
+	if !g.settings.capturing {
+		if g.actions.IsJustPressed(input.Screenshot) {
+			c.screenshot = true
+		}
+		if g.actions.IsJustPressed(input.Record) {
+			g.saveRecording()
+		}
+	}
+
+	if g.screen != Screen.Play || g.world == nil {
+		return
+	}
+	if g.world != c.world {
+		c.world, c.tick = g.world, g.world.Tick
+	}
+	if g.world.Tick == c.tick || g.world.Tick%render.FrameTicks != 0 {
+		return
+	}
+	c.tick = g.world.Tick
+	if c.frames == nil {
+		c.frames = make([]*simulation.World, recordingSeconds*simulation.TicksPerSecond/render.FrameTicks)
+	}
+	if c.frames[c.next] == nil {
+		c.frames[c.next] = g.world.Clone()
+	} else {
+		c.frames[c.next].CopyFrom(g.world)
+	}
+	c.next = (c.next + 1) % len(c.frames)
+	c.count = min(c.count+1, len(c.frames))
+}
+
+// saveRecording draws the kept frames into a GIF in the background.
+func (g *Game) saveRecording() {
+	var c = &g.capture
+	if c.count == 0 {
+		return
+	}
+	var worlds = make([]*simulation.World, 0, c.count)
+	for i := 0; i < c.count; i++ {
+		var frame = c.frames[(c.next-c.count+i+len(c.frames))%len(c.frames)]
+		worlds = append(worlds, frame.Clone())
+	}
+	var palette = g.palette()
+	var path = time.Now().Format(captureName) + ".gif"
+	go func() {
+		if err := writeGIF(path, palette, func(f *os.File, r *render.Renderer) error {
+			return render.WorldsGIF(f, worlds, r)
+		}); err != nil {
+			log.Printf("failed to save recording: %v", err)
+			return
+		}
+		log.Printf("saved recording to %s", path)
+	}()
+}
+
+func writeGIF(path string, palette theme.Palette, write func(*os.File, *render.Renderer) error) error {
+	assets, err := loadRenderAssets()
+	if err != nil {
+		return err
+	}
+	r, err := render.New(assets, palette)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := write(f, r); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// takeScreenshot saves frame as a PNG when one was asked for.
+func (g *Game) takeScreenshot(frame *ebiten.Image) {
+	if !g.capture.screenshot {
+		return
+	}
+	g.capture.screenshot = false
+	var img = image.NewRGBA(image.Rectangle{Max: frame.Bounds().Size()})
+	frame.ReadPixels(img.Pix)
+	var path = time.Now().Format(captureName) + ".png"
+	go func() {
+		f, err := os.Create(path)
+		if err == nil {
+			err = png.Encode(f, img)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			log.Printf("failed to save screenshot: %v", err)
+			return
+		}
+		log.Printf("saved screenshot to %s", path)
+	}()
+}
@@ -0,0 +1,42 @@
+// Command gym lets agents written in other languages play Spaders through
+// the gym package. Requests and responses are JSON, one per line, over
+// stdin and stdout, or over TCP with -addr.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/akshayxml/spaders/gym"
+	"github.com/akshayxml/spaders/simulation"
+)
+
+func main() {
+	addr := flag.String("addr", "", "serve over TCP on this address, e.g. 127.0.0.1:7779, instead of stdin and stdout")
+	difficulty := flag.Int("difficulty", 1, "difficulty from 1 to 3")
+	features := flag.Bool("features", false, "observe the feature vector (the default when -pixels is not set)")
+	pixels := flag.Bool("pixels", false, "observe a downscaled grayscale screen")
+	scale := flag.Int("scale", gym.DefaultScale, "how many times smaller than the screen the pixel buffer is")
+	frameSkip := flag.Int("frame-skip", 1, "ticks each action is held for")
+	maxSeconds := flag.Int64("max-seconds", gym.DefaultMaxTicks/simulation.TicksPerSecond, "game seconds after which an episode is cut short")
+	lifePenalty := flag.Float64("life-penalty", 0, "reward taken away for every life lost")
+	flag.Parse()
+
+	var options = gym.Options{
+		Difficulty:  *difficulty,
+		Features:    *features,
+		Pixels:      *pixels,
+		Scale:       *scale,
+		FrameSkip:   *frameSkip,
+		MaxTicks:    *maxSeconds * simulation.TicksPerSecond,
+		LifePenalty: *lifePenalty,
+	}
+	if *addr != "" {
+		log.Printf("gym: serving on %s", *addr)
+		log.Fatal(gym.ListenAndServe(*addr, options))
+	}
+	if err := gym.Serve(os.Stdin, os.Stdout, options); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,25 @@
+// Command leaderboard serves the Spaders high score tables. Every submitted
+// score comes with the replay of its game, which is simulated again before
+// the score is accepted.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/akshayxml/spaders/leaderboard"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to serve the API on")
+	dir := flag.String("dir", "leaderboard-data", "directory the entries and replays are stored in")
+	flag.Parse()
+
+	store, err := leaderboard.Open(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("leaderboard: serving %s on %s", *dir, *addr)
+	log.Fatal(http.ListenAndServe(*addr, leaderboard.NewServer(store)))
+}
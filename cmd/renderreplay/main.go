@@ -0,0 +1,76 @@
+// Command renderreplay draws a replay into an animated GIF without opening a
+// window, the way the game would have shown it. It only uses the render
+// package, so it runs on machines without a display or a GPU.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akshayxml/spaders/models/EnemyType"
+	"github.com/akshayxml/spaders/render"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/theme"
+)
+
+func main() {
+	out := flag.String("out", "replay.gif", "where to write the GIF")
+	assets := flag.String("assets", "assets", "directory holding the game's pictures and font")
+	var names []string
+	for _, p := range theme.Palettes {
+		names = append(names, p.Name)
+	}
+	themeName := flag.String("theme", theme.Neon.Name, "palette to draw with: "+strings.Join(names, ", "))
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: renderreplay [flags] replay.json")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var palette = theme.Find(*themeName)
+	if palette.Name != *themeName {
+		log.Fatalf("unknown theme %q", *themeName)
+	}
+
+	data, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var replay simulation.Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		log.Fatal(err)
+	}
+	loaded, err := render.LoadAssets(filepath.Join(*assets, "bg.jpg"), map[EnemyType.EnemyType]string{
+		EnemyType.One:   filepath.Join(*assets, "enemyOne.png"),
+		EnemyType.Two:   filepath.Join(*assets, "enemyTwo.png"),
+		EnemyType.Three: filepath.Join(*assets, "enemyThree.png"),
+	}, filepath.Join(*assets, "CosmicAlien.ttf"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	r, err := render.New(loaded, palette)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := render.ReplayGIF(f, &replay, r); err != nil {
+		f.Close()
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
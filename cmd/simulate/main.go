@@ -0,0 +1,257 @@
+// Command simulate plays many headless single player games at once with one
+// of the bots and reports how long they last, what they score and how they
+// end. The constants of the difficulty ramp can be overridden to see how a
+// change would play out before making it.
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/akshayxml/spaders/bot"
+	"github.com/akshayxml/spaders/events"
+	"github.com/akshayxml/spaders/simulation"
+)
+
+var difficultyNames = []string{"EASY", "MEDIUM", "DEATHZONE"}
+
+// How a game ended.
+const (
+	cleared = "cleared"
+	shot    = "shot"
+	invaded = "invaded"
+	timeout = "timeout"
+)
+
+var outcomes = []string{cleared, shot, invaded, timeout}
+
+type result struct {
+	seed       int64
+	difficulty int
+	ticks      int64
+	score      int
+	outcome    string
+	// Invaders killed and enemy bullets shot down.
+	kills    int
+	shotDown int
+}
+
+type options struct {
+	bot      string
+	tuning   simulation.Tuning
+	maxTicks int64
+}
+
+func play(seed int64, difficulty int, opts options) (result, error) {
+	player, err := bot.New(opts.bot, 0, seed)
+	if err != nil {
+		return result{}, err
+	}
+	var w = simulation.New(seed, difficulty, 1)
+	w.Tuning = opts.tuning
+	var res = result{seed: seed, difficulty: difficulty, outcome: timeout}
+	var bus = events.NewBus()
+	events.Subscribe(bus, func(e events.EnemyKilled) { res.kills++ })
+	events.Subscribe(bus, func(e events.BulletsCollided) { res.shotDown++ })
+	events.Subscribe(bus, func(e events.PlayerHit) {
+		res.outcome = shot
+		if e.Invaded {
+			res.outcome = invaded
+		}
+	})
+	var inputs = make([]simulation.Input, 1)
+	for w.CanPlay() && w.Tick < opts.maxTicks {
+		inputs[0] = player.Input(w)
+		w.Step(inputs)
+		bus.PublishAll(w.Events)
+	}
+	res.ticks = w.Tick
+	res.score = w.Score
+	if w.EnemyState.EnemyCount == 0 {
+		res.outcome = cleared
+	} else if w.CanPlay() {
+		res.outcome = timeout
+	}
+	return res, nil
+}
+
+// run plays games games on each difficulty spread over workers goroutines.
+// Results come back in seed order whatever the number of workers.
+func run(difficulties []int, games int, firstSeed int64, workers int, opts options) ([]result, error) {
+	var results = make([]result, len(difficulties)*games)
+	var jobs = make(chan int)
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				var res, err = play(firstSeed+int64(job%games), difficulties[job/games], opts)
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+				}
+				results[job] = res
+			}
+		}()
+	}
+	for job := range results {
+		jobs <- job
+	}
+	close(jobs)
+	wg.Wait()
+	return results, firstErr
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[min(int(p*float64(len(sorted))), len(sorted)-1)]
+}
+
+func printDistribution(name string, values []float64) {
+	var sorted = append([]float64{}, values...)
+	sort.Float64s(sorted)
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	fmt.Printf("  %-10s %8.1f %8.1f %8.1f %8.1f %8.1f %8.1f %8.1f\n", name, sum/float64(len(sorted)),
+		sorted[0], percentile(sorted, 0.1), percentile(sorted, 0.5), percentile(sorted, 0.9), percentile(sorted, 0.99), sorted[len(sorted)-1])
+}
+
+// printHistogram draws values in bins equally wide bins as bars of #.
+func printHistogram(name string, values []float64, bins int) {
+	var low, high = values[0], values[0]
+	for _, v := range values {
+		low, high = min(low, v), max(high, v)
+	}
+	var width = (high - low) / float64(bins)
+	if width == 0 {
+		width = 1
+	}
+	var counts = make([]int, bins)
+	var most = 0
+	for _, v := range values {
+		var bin = min(int((v-low)/width), bins-1)
+		counts[bin]++
+		most = max(most, counts[bin])
+	}
+	fmt.Printf("  %s\n", name)
+	for i, count := range counts {
+		fmt.Printf("  %8.1f - %-8.1f %6d %s\n", low+width*float64(i), low+width*float64(i+1), count,
+			strings.Repeat("#", count*40/most))
+	}
+}
+
+func report(difficulty int, results []result) {
+	var survival, scores, kills, shotDown []float64
+	var outcomeCounts = map[string]int{}
+	for _, res := range results {
+		survival = append(survival, float64(res.ticks)/simulation.TicksPerSecond)
+		scores = append(scores, float64(res.score))
+		kills = append(kills, float64(res.kills))
+		shotDown = append(shotDown, float64(res.shotDown))
+		outcomeCounts[res.outcome]++
+	}
+
+	fmt.Printf("%s, %d games\n", difficultyNames[difficulty-1], len(results))
+	var parts []string
+	for _, outcome := range outcomes {
+		parts = append(parts, fmt.Sprintf("%s %.1f%%", outcome, 100*float64(outcomeCounts[outcome])/float64(len(results))))
+	}
+	fmt.Printf("  ended by   %s\n", strings.Join(parts, ", "))
+	fmt.Printf("  %-10s %8s %8s %8s %8s %8s %8s %8s\n", "", "MEAN", "MIN", "P10", "P50", "P90", "P99", "MAX")
+	printDistribution("seconds", survival)
+	printDistribution("score", scores)
+	printDistribution("kills", kills)
+	printDistribution("shot down", shotDown)
+	printHistogram("seconds survived", survival, 10)
+	fmt.Println()
+}
+
+func writeCSV(path string, results []result) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var w = csv.NewWriter(f)
+	w.Write([]string{"seed", "difficulty", "ticks", "score", "outcome", "kills", "shot_down"})
+	for _, res := range results {
+		w.Write([]string{
+			strconv.FormatInt(res.seed, 10),
+			strconv.Itoa(res.difficulty),
+			strconv.FormatInt(res.ticks, 10),
+			strconv.Itoa(res.score),
+			res.outcome,
+			strconv.Itoa(res.kills),
+			strconv.Itoa(res.shotDown),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func main() {
+	games := flag.Int("games", 1000, "games to play on each difficulty")
+	botName := flag.String("bot", "autopilot", "bot to play with: "+strings.Join(bot.Names, ", "))
+	difficulty := flag.Int("difficulty", 0, "difficulty from 1 to 3, or 0 for all of them")
+	seed := flag.Int64("seed", 1, "seed of the first game; each game adds one")
+	workers := flag.Int("workers", runtime.NumCPU(), "games played at the same time")
+	maxMinutes := flag.Int64("max-minutes", 30, "game minutes after which a game is stopped")
+	csvPath := flag.String("csv", "", "also write every game to this CSV file")
+
+	var tuning = simulation.DefaultTuning
+	flag.Float64Var(&tuning.HorizontalSpeedLimit, "speed-limit", tuning.HorizontalSpeedLimit, "fastest the formation moves sideways")
+	flag.IntVar(&tuning.HorizontalSpeedChangeIntervalMs, "speed-interval", tuning.HorizontalSpeedChangeIntervalMs, "how fast the formation speeds up, in ms")
+	flag.IntVar(&tuning.VerticalMoveIntervalMs, "vertical-interval", tuning.VerticalMoveIntervalMs, "time between the formation's steps down, in ms")
+	flag.IntVar(&tuning.FireRateLimit, "fire-limit", tuning.FireRateLimit, "highest enemy fire rate, in chances per hundred ticks per difficulty")
+	flag.IntVar(&tuning.FireRateLimitChangeIntervalMs, "fire-interval", tuning.FireRateLimitChangeIntervalMs, "time for the fire rate to go up by one, in ms")
+	flag.Parse()
+
+	if tuning.HorizontalSpeedChangeIntervalMs < 1 || tuning.VerticalMoveIntervalMs < 1 || tuning.FireRateLimitChangeIntervalMs < 1 {
+		log.Fatal("intervals must be at least 1 ms")
+	}
+	if *games < 1 || *workers < 1 {
+		log.Fatal("-games and -workers must be at least 1")
+	}
+	if _, err := bot.New(*botName, 0, 0); err != nil {
+		log.Fatal(err)
+	}
+	var difficulties = []int{1, 2, 3}
+	if *difficulty != 0 {
+		if *difficulty < 1 || *difficulty > 3 {
+			log.Fatal("-difficulty must be between 0 and 3")
+		}
+		difficulties = []int{*difficulty}
+	}
+
+	var opts = options{bot: *botName, tuning: tuning, maxTicks: *maxMinutes * 60 * simulation.TicksPerSecond}
+	results, err := run(difficulties, *games, *seed, *workers, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("bot %s, %+v\n\n", *botName, tuning)
+	for i, difficulty := range difficulties {
+		report(difficulty, results[i**games:(i+1)**games])
+	}
+	if *csvPath != "" {
+		if err := writeCSV(*csvPath, results); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
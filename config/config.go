@@ -0,0 +1,88 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/akshayxml/spaders/input"
+	"github.com/akshayxml/spaders/snapshot"
+	"github.com/akshayxml/spaders/theme"
+)
+
+const (
+	Version  = 1
+	fileName = "config.json"
+)
+
+// Version 0 is the config file from before it had a version, which only
+// needed wrapping.
+var schema = snapshot.New("config", Version, func() any { return Default() }).
+	Migrate(0, func(doc map[string]any) error { return nil })
+
+type Config struct {
+	Keyboard        input.Bindings `json:"keyboard"`
+	Player2Keyboard input.Bindings `json:"player2Keyboard"`
+	GamepadDeadZone float64        `json:"gamepadDeadZone"`
+	PlayerName      string         `json:"playerName"`
+	LeaderboardURL  string         `json:"leaderboardURL"`
+	CRT             bool           `json:"crt"`
+	Theme           string         `json:"theme"`
+	SmoothScaling   bool           `json:"smoothScaling"`
+	Fullscreen      bool           `json:"fullscreen"`
+}
+
+func Default() *Config {
+	return &Config{
+		Keyboard:        input.DefaultBindings(),
+		Player2Keyboard: input.DefaultPlayer2Bindings(),
+		GamepadDeadZone: input.DefaultDeadZone,
+		PlayerName:      "PLAYER",
+		Theme:           theme.Neon.Name,
+	}
+}
+
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "spaders", fileName), nil
+}
+
+// Load reads the config file, falling back to the defaults when it does not
+// exist yet.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return Default(), err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Default(), err
+	}
+	var cfg = Default()
+	if err := schema.Decode(data, cfg); err != nil {
+		return Default(), err
+	}
+	return cfg, nil
+}
+
+func (c *Config) Save() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := schema.Encode(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
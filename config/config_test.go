@@ -0,0 +1,19 @@
+package config
+
+import (
+	"flag"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "write the golden file of the current version")
+
+func TestGolden(t *testing.T) {
+	if *update {
+		if err := schema.UpdateGolden("../testdata/snapshots"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := schema.CheckGolden("../testdata/snapshots"); err != nil {
+		t.Fatal(err)
+	}
+}
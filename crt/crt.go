@@ -0,0 +1,101 @@
+// Package crt makes the screen look like it is shown on an old CRT monitor:
+// scanlines, a curved tube, glowing phosphor, color fringes and darker
+// corners. The effect runs as a Kage shader on the GPU. Apply does the same
+// maths in Go, as a fallback for when the shader can't be used.
+package crt
+
+import (
+	"image"
+	"math"
+)
+
+type Params struct {
+	// How much darker every other line is, from 0 to 1.
+	Scanlines float64
+	// How far the picture bulges out in the middle.
+	Curvature float64
+	// How much bright pixels, like the neon green, glow onto their
+	// neighbours.
+	Bloom float64
+	// How many pixels the red and blue channels are pulled apart.
+	Aberration float64
+	// How much darker the corners are than the middle.
+	Vignette float64
+}
+
+var Default = Params{
+	Scanlines:  0.3,
+	Curvature:  0.04,
+	Bloom:      0.4,
+	Aberration: 1,
+	Vignette:   0.35,
+}
+
+// Offsets of the pixels averaged for the glow around each pixel.
+var bloomTaps = [8][2]float64{{-2, 0}, {2, 0}, {0, -2}, {0, 2}, {-2, -2}, {2, -2}, {-2, 2}, {2, 2}}
+
+// Apply runs the effect over src and writes the result to dst, which must
+// be the same size. It follows the shader in assets/crt.kage step by step.
+func Apply(dst, src *image.RGBA, p Params) {
+	var bounds = src.Bounds()
+	var width, height = float64(bounds.Dx()), float64(bounds.Dy())
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			var c = shade(src, float64(x)+0.5, float64(y)+0.5, width, height, p)
+			var i = dst.PixOffset(dst.Rect.Min.X+x, dst.Rect.Min.Y+y)
+			for j := 0; j < 3; j++ {
+				dst.Pix[i+j] = uint8(math.Round(c[j] * 0xff))
+			}
+			dst.Pix[i+3] = 0xff
+		}
+	}
+}
+
+// shade works out the color of the pixel whose center is at x, y.
+func shade(src *image.RGBA, x, y, width, height float64, p Params) [3]float64 {
+	// Where the pixel is on the tube, from -1 to 1 across and down.
+	var cx, cy = x/width*2 - 1, y/height*2 - 1
+	cx, cy = cx*(1+p.Curvature*cy*cy), cy*(1+p.Curvature*cx*cx)
+	if math.Abs(cx) > 1 || math.Abs(cy) > 1 {
+		return [3]float64{}
+	}
+	var sx, sy = (cx + 1) / 2 * width, (cy + 1) / 2 * height
+
+	var c = [3]float64{
+		at(src, sx+p.Aberration, sy)[0],
+		at(src, sx, sy)[1],
+		at(src, sx-p.Aberration, sy)[2],
+	}
+	var glow [3]float64
+	for _, tap := range bloomTaps {
+		var t = at(src, sx+tap[0], sy+tap[1])
+		for j := range glow {
+			glow[j] += t[j]
+		}
+	}
+	var scanline = 1.0
+	if int(math.Floor(y))%2 == 1 {
+		scanline = 1 - p.Scanlines
+	}
+	var vignette = clamp(1-p.Vignette*(cx*cx+cy*cy)/2, 0, 1)
+	for j := range c {
+		c[j] = clamp((c[j]+glow[j]/8*p.Bloom)*scanline*vignette, 0, 1)
+	}
+	return c
+}
+
+// at reads the pixel that x, y falls in, or black outside the image, like
+// a shader sampling with the nearest filter.
+func at(src *image.RGBA, x, y float64) [3]float64 {
+	var px, py = int(math.Floor(x)), int(math.Floor(y))
+	var bounds = src.Bounds()
+	if px < 0 || py < 0 || px >= bounds.Dx() || py >= bounds.Dy() {
+		return [3]float64{}
+	}
+	var i = src.PixOffset(bounds.Min.X+px, bounds.Min.Y+py)
+	return [3]float64{float64(src.Pix[i]) / 0xff, float64(src.Pix[i+1]) / 0xff, float64(src.Pix[i+2]) / 0xff}
+}
+
+func clamp(v, low, high float64) float64 {
+	return min(max(v, low), high)
+}
@@ -0,0 +1,112 @@
+package crt
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "write the golden screenshot instead of checking it")
+
+const golden = "../testdata/crt/scene.png"
+
+// Biggest difference allowed in any channel of any pixel, to leave room for
+// floating point rounding to differ between machines.
+const tolerance = 2
+
+// scene draws something like a frame of the game: invaders, bunkers, a
+// cannon, bullets and the line along the bottom, on black.
+func scene() *image.RGBA {
+	var img = image.NewRGBA(image.Rect(0, 0, 640, 480))
+	var fill = func(x, y, width, height int, c color.Color) {
+		draw.Draw(img, image.Rect(x, y, x+width, y+height), image.NewUniform(c), image.Point{}, draw.Src)
+	}
+	var neonGreen = color.RGBA{0x39, 0xFF, 0x14, 0xFF}
+	fill(0, 0, 640, 480, color.Black)
+	for row := 0; row < 5; row++ {
+		for col := 0; col < 10; col++ {
+			fill(60+col*48, 60+row*36, 24, 16, color.White)
+		}
+	}
+	for i := 0; i < 4; i++ {
+		fill(80+i*140, 360, 60, 24, neonGreen)
+	}
+	fill(300, 420, 30, 14, neonGreen)
+	fill(314, 300, 2, 12, color.White)
+	fill(200, 250, 4, 12, color.White)
+	fill(50, 469, 540, 2, neonGreen)
+	return img
+}
+
+func readPNG(path string) (*image.RGBA, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	var rgba = image.NewRGBA(image.Rectangle{Max: img.Bounds().Size()})
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba, nil
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// difference returns the biggest difference of any channel between a and b
+// and how many pixels differ by more than the tolerance.
+func difference(a, b *image.RGBA) (int, int) {
+	var most, pixels = 0, 0
+	for i := 0; i < len(a.Pix); i += 4 {
+		var pixel = 0
+		for j := i; j < i+4; j++ {
+			pixel = max(pixel, int(a.Pix[j])-int(b.Pix[j]), int(b.Pix[j])-int(a.Pix[j]))
+		}
+		most = max(most, pixel)
+		if pixel > tolerance {
+			pixels++
+		}
+	}
+	return most, pixels
+}
+
+// TestApplyGolden runs the CPU version of the effect over the scene. It
+// says nothing about the shader, which needs a GPU to run. After changing
+// the effect on purpose, look at the picture written with -update before
+// committing it.
+func TestApplyGolden(t *testing.T) {
+	var src = scene()
+	var got = image.NewRGBA(src.Bounds())
+	Apply(got, src, Default)
+	if *update {
+		if err := writePNG(golden, got); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := readPNG(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want.Bounds() != got.Bounds() {
+		t.Fatalf("%s is %v, the scene is %v", golden, want.Bounds().Size(), got.Bounds().Size())
+	}
+	if most, pixels := difference(want, got); pixels > 0 {
+		t.Errorf("%d pixels differ from %s, by up to %d", pixels, golden, most)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"image"
+
+	"github.com/akshayxml/spaders/crt"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// crtRenderer runs finished frames through the CRT effect.
+type crtRenderer struct {
+	params crt.Params
+	shader *ebiten.Shader
+	out    *ebiten.Image
+	// Used to run the effect on the CPU when there is no shader.
+	src, dst *image.RGBA
+}
+
+// newCRTRenderer compiles the Kage shader in source. Without a source, or
+// when it doesn't compile, the effect is run on the CPU instead, and the
+// error says why.
+func newCRTRenderer(source []byte, p crt.Params) (*crtRenderer, error) {
+	var r = &crtRenderer{params: p}
+	if source == nil {
+		return r, nil
+	}
+	shader, err := ebiten.NewShader(source)
+	if err != nil {
+		return r, err
+	}
+	r.shader = shader
+	return r, nil
+}
+
+// Apply runs the effect over src and returns the result, which stays valid
+// until the next call.
+func (r *crtRenderer) Apply(src *ebiten.Image) *ebiten.Image {
+	var size = src.Bounds().Size()
+	if r.out == nil || r.out.Bounds().Size() != size {
+		if r.out != nil {
+			r.out.Deallocate()
+		}
+		r.out = ebiten.NewImage(size.X, size.Y)
+	}
+	if r.shader == nil {
+		r.applyCPU(src)
+		return r.out
+	}
+	r.out.Clear()
+	r.out.DrawRectShader(size.X, size.Y, r.shader, &ebiten.DrawRectShaderOptions{
+		Images: [4]*ebiten.Image{src},
+		Uniforms: map[string]any{
+			"Scanlines":  float32(r.params.Scanlines),
+			"Curvature":  float32(r.params.Curvature),
+			"Bloom":      float32(r.params.Bloom),
+			"Aberration": float32(r.params.Aberration),
+			"Vignette":   float32(r.params.Vignette),
+		},
+	})
+	return r.out
+}
+
+func (r *crtRenderer) applyCPU(src *ebiten.Image) {
+	var bounds = image.Rectangle{Max: src.Bounds().Size()}
+	if r.src == nil || r.src.Bounds() != bounds {
+		r.src, r.dst = image.NewRGBA(bounds), image.NewRGBA(bounds)
+	}
+	src.ReadPixels(r.src.Pix)
+	crt.Apply(r.dst, r.src, r.params)
+	r.out.WritePixels(r.dst.Pix)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/akshayxml/spaders/input"
+	"github.com/akshayxml/spaders/models"
+	"github.com/akshayxml/spaders/models/EntityState"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/sprites"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// The overlay has its own colors so that it stands out whatever the theme.
+var (
+	hitboxColor     = color.RGBA{0xFF, 0x00, 0xFF, 0xFF}
+	trajectoryColor = color.RGBA{0xFF, 0xFF, 0x00, 0x80}
+	boundsColor     = color.RGBA{0x00, 0xFF, 0xFF, 0xFF}
+)
+
+// How many updates each tick lasts at each step of slow motion.
+var slowMotion = []int{1, 2, 4, 8}
+
+type debugState struct {
+	on        bool
+	slowdown  int
+	updates   int
+	frameStep bool
+}
+
+func (g *Game) updateDebug() {
+	if g.settings.capturing {
+		return
+	}
+	if g.actions.IsJustPressed(input.Debug) {
+		g.debug.on = !g.debug.on
+		g.debug.slowdown = 0
+	}
+	if !g.debug.on {
+		return
+	}
+	if g.actions.IsJustPressed(input.SlowMotion) {
+		g.debug.slowdown = (g.debug.slowdown + 1) % len(slowMotion)
+	}
+	if g.actions.IsJustPressed(input.FrameStep) && g.paused {
+		g.debug.frameStep = true
+	}
+}
+
+// debugStep says whether a paused game should go forward a single tick.
+func (g *Game) debugStep() bool {
+	if !g.debug.frameStep {
+		return false
+	}
+	g.debug.frameStep = false
+	g.assisted = true
+	return true
+}
+
+// debugHoldsTick says whether slow motion skips this update.
+func (g *Game) debugHoldsTick() bool {
+	if slowMotion[g.debug.slowdown] == 1 {
+		return false
+	}
+	g.assisted = true
+	g.debug.updates++
+	return g.debug.updates%slowMotion[g.debug.slowdown] != 0
+}
+
+func strokeBox(screen *ebiten.Image, x, y, width, height float64, clr color.Color) {
+	vector.StrokeRect(screen, float32(x), float32(y), float32(width), float32(height), 1, clr, false)
+}
+
+// drawBullet draws the path the bullet is tested along for its last move,
+// the box other bullets hit it in, and a faint line to where it will leave
+// the screen.
+func drawBullet(screen *ebiten.Image, b models.Bullet, end float64) {
+	var from, to = b.Path()
+	vector.StrokeLine(screen, float32(b.Position.X), float32(from.Y), float32(b.Position.X), float32(end), 1, trajectoryColor, false)
+	vector.StrokeLine(screen, float32(from.X), float32(from.Y), float32(to.X), float32(to.Y), 3, hitboxColor, false)
+	strokeBox(screen, b.Position.X-models.BulletHitWidth, b.Position.Y, 2*models.BulletHitWidth, b.Height, hitboxColor)
+}
+
+// DrawDebug draws the boxes collisions are tested against, the bounds the
+// formation turns at and the numbers behind the difficulty ramp.
+func (g *Game) DrawDebug(screen *ebiten.Image) {
+	var w = g.world
+	for _, x := range []float64{simulation.LeftBoundary, simulation.RightBoundary} {
+		vector.StrokeLine(screen, float32(x), 0, float32(x), float32(logicalHeight), 1, boundsColor, false)
+	}
+
+	var left, top, right, bottom = logicalWidth, logicalHeight, 0.0, 0.0
+	var alive = 0
+	for _, enemy := range w.Enemies {
+		if enemy.State == EntityState.Alive {
+			strokeBox(screen, enemy.Position.X, enemy.Position.Y, enemy.GetEnemyWidth(), enemy.GetEnemyHeight(), hitboxColor)
+			left, top = min(left, enemy.Position.X), min(top, enemy.Position.Y)
+			right, bottom = max(right, enemy.Position.X+enemy.GetEnemyWidth()), max(bottom, enemy.Position.Y+enemy.GetEnemyHeight())
+			alive++
+		}
+	}
+	if alive > 0 {
+		strokeBox(screen, left-2, top-2, right-left+4, bottom-top+4, boundsColor)
+	}
+
+	for _, sprite := range w.BunkerSprites {
+		if sprite.Height > 0 {
+			strokeBox(screen, sprite.Position.X, sprite.Position.Y, sprite.Width, sprite.Height, hitboxColor)
+		}
+	}
+	for _, player := range w.Players {
+		if player.Lives > 0 {
+			for _, rect := range sprites.GetPlayerRectangles() {
+				strokeBox(screen, player.Position.X+rect.Position.X, player.Position.Y+rect.Position.Y, rect.Width, rect.Height, hitboxColor)
+			}
+		}
+		if player.Bullet.IsActive {
+			drawBullet(screen, player.Bullet, 0)
+		}
+	}
+	for _, bullet := range w.EnemyBullets.Values() {
+		drawBullet(screen, bullet, logicalHeight)
+	}
+
+	var slowdown = "OFF"
+	if slowMotion[g.debug.slowdown] > 1 {
+		slowdown = fmt.Sprintf("1/%d", slowMotion[g.debug.slowdown])
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf(
+		"TICK %d  TPS %.1f  FPS %.1f\n"+
+			"SPEED %.2f  DIRECTION %+d  FIRE RATE %d (%d%% A TICK)\n"+
+			"INVADERS %d  ENEMY BULLETS %d  ENTITIES %d\n"+
+			"SLOW MOTION %s (%s)  STEP WHILE PAUSED (%s)",
+		w.Tick, ebiten.ActualTPS(), ebiten.ActualFPS(),
+		w.EnemyState.HorizontalSpeed, w.EnemyState.HorizontalDirection, w.EnemyState.EnemyFireRate, w.EnemyState.EnemyFireRate+1,
+		alive, w.EnemyBullets.Len(), w.Entities.Count(),
+		slowdown, g.keyName(input.SlowMotion), g.keyName(input.FrameStep),
+	), 50, 36)
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/akshayxml/spaders/input"
+	"github.com/akshayxml/spaders/models/Screen"
+	"github.com/akshayxml/spaders/theme"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	crtDisplayItem        = 0
+	themeDisplayItem      = 1
+	scalingDisplayItem    = 2
+	fullscreenDisplayItem = 3
+	displayItemCount      = 4
+)
+
+var gelImage = ebiten.NewImage(1, 1)
+
+// multiply blends what is drawn by multiplying the colors under it.
+var multiply = ebiten.Blend{
+	BlendFactorSourceRGB:        ebiten.BlendFactorZero,
+	BlendFactorSourceAlpha:      ebiten.BlendFactorZero,
+	BlendFactorDestinationRGB:   ebiten.BlendFactorSourceColor,
+	BlendFactorDestinationAlpha: ebiten.BlendFactorOne,
+	BlendOperationRGB:           ebiten.BlendOperationAdd,
+	BlendOperationAlpha:         ebiten.BlendOperationAdd,
+}
+
+func init() {
+	gelImage.Fill(color.White)
+}
+
+func (g *Game) palette() theme.Palette {
+	return theme.Find(g.config.Theme)
+}
+
+// nextTheme moves to the palette step places along the list.
+func (g *Game) nextTheme(step int) {
+	var i = 0
+	for j, p := range theme.Palettes {
+		if p.Name == g.palette().Name {
+			i = j
+		}
+	}
+	g.config.Theme = theme.Palettes[(i+step+len(theme.Palettes))%len(theme.Palettes)].Name
+}
+
+func drawGels(screen *ebiten.Image, palette theme.Palette) {
+	for _, gel := range palette.Gels {
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Scale(float64(screen.Bounds().Dx()), gel.Bottom-gel.Top)
+		opts.GeoM.Translate(0, gel.Top)
+		opts.ColorScale.ScaleWithColor(gel.Color)
+		opts.Blend = multiply
+		screen.DrawImage(gelImage, opts)
+	}
+}
+
+type displayState struct {
+	selection int
+}
+
+func onOff(on bool) string {
+	if on {
+		return "< ON >"
+	}
+	return "< OFF >"
+}
+
+func scalingName(smooth bool) string {
+	if smooth {
+		return "< SMOOTH >"
+	}
+	return "< INTEGER >"
+}
+
+func (g *Game) updateDisplay() {
+	if g.actions.IsJustPressed(input.Back) {
+		g.screen = Screen.Menu
+		return
+	}
+	if g.actions.IsJustPressed(input.MenuDown) {
+		g.display.selection = (g.display.selection + 1) % displayItemCount
+	}
+	if g.actions.IsJustPressed(input.MenuUp) {
+		g.display.selection = (g.display.selection + displayItemCount - 1) % displayItemCount
+	}
+	var step = 0
+	if g.actions.IsJustPressed(input.MoveLeft) {
+		step = -1
+	} else if g.actions.IsJustPressed(input.MoveRight) || g.actions.IsJustPressed(input.Confirm) {
+		step = 1
+	}
+	if step == 0 {
+		return
+	}
+	if g.display.selection == crtDisplayItem {
+		g.config.CRT = !g.config.CRT
+	} else if g.display.selection == themeDisplayItem {
+		g.nextTheme(step)
+	} else if g.display.selection == scalingDisplayItem {
+		g.config.SmoothScaling = !g.config.SmoothScaling
+	} else if g.display.selection == fullscreenDisplayItem {
+		g.toggleFullscreen()
+		return
+	}
+	g.saveConfig()
+}
+
+func (g *Game) DrawDisplay(screen *ebiten.Image, palette theme.Palette) {
+	face := &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   bigFontSize,
+	}
+	textOp := &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), 80)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	textOp.PrimaryAlign = text.AlignCenter
+	textOp.SecondaryAlign = text.AlignCenter
+	text.Draw(screen, "DISPLAY", face, textOp)
+
+	face = &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   normalFontSize,
+	}
+	var label = "CRT EFFECT"
+	if g.display.selection == crtDisplayItem {
+		label = "->" + label
+	}
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(100, 130)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	text.Draw(screen, label, face, textOp)
+
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(340, 130)
+	textOp.ColorScale.ScaleWithColor(palette.Text)
+	text.Draw(screen, onOff(g.config.CRT), face, textOp)
+
+	label = "THEME"
+	if g.display.selection == themeDisplayItem {
+		label = "->" + label
+	}
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(100, 155)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	text.Draw(screen, label, face, textOp)
+
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(340, 155)
+	textOp.ColorScale.ScaleWithColor(palette.Text)
+	text.Draw(screen, "< "+palette.Name+" >", face, textOp)
+
+	label = "SCALING"
+	if g.display.selection == scalingDisplayItem {
+		label = "->" + label
+	}
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(100, 180)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	text.Draw(screen, label, face, textOp)
+
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(340, 180)
+	textOp.ColorScale.ScaleWithColor(palette.Text)
+	text.Draw(screen, scalingName(g.config.SmoothScaling), face, textOp)
+
+	label = "FULLSCREEN"
+	if g.display.selection == fullscreenDisplayItem {
+		label = "->" + label
+	}
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(100, 205)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	text.Draw(screen, label, face, textOp)
+
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(340, 205)
+	textOp.ColorScale.ScaleWithColor(palette.Text)
+	text.Draw(screen, onOff(g.config.Fullscreen), face, textOp)
+
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), logicalHeight-40)
+	textOp.ColorScale.ScaleWithColor(palette.Text)
+	textOp.PrimaryAlign = text.AlignCenter
+	text.Draw(screen, "PRESS "+g.keyName(input.Back)+" TO GO BACK", face, textOp)
+}
@@ -0,0 +1,69 @@
+// Package ecs keeps game objects as entities with components stored by
+// type, and runs systems over them in a fixed order.
+//
+// Everything iterates in a defined order, never in map order, so two
+// simulations doing the same things stay identical.
+package ecs
+
+import "github.com/akshayxml/spaders/models/Datatypes"
+
+// Entity is an id; what it is depends on which stores hold a component for
+// it. Ids are never reused.
+type Entity uint32
+
+type store interface {
+	Remove(e Entity)
+}
+
+// Registry hands out entities and removes them from every store they are in
+// when they are destroyed.
+type Registry struct {
+	next   Entity
+	alive  *Datatypes.Set[Entity]
+	stores []store
+}
+
+func NewRegistry() *Registry {
+	return &Registry{next: 1, alive: Datatypes.NewSet[Entity]()}
+}
+
+func (r *Registry) Create() Entity {
+	var e = r.next
+	r.next++
+	r.alive.Add(e)
+	return e
+}
+
+func (r *Registry) Destroy(e Entity) {
+	if !r.alive.Has(e) {
+		return
+	}
+	r.alive.Remove(e)
+	for _, s := range r.stores {
+		s.Remove(e)
+	}
+}
+
+func (r *Registry) Alive(e Entity) bool {
+	return r.alive.Has(e)
+}
+
+func (r *Registry) Count() int {
+	return r.alive.Size()
+}
+
+// CopyFrom makes r hand out the same entities as src. The stores are copied
+// on their own.
+func (r *Registry) CopyFrom(src *Registry) {
+	r.next = src.next
+	r.alive.CopyFrom(src.alive)
+}
+
+// Systems run one after another in the order they are listed.
+type Systems[W any] []func(W)
+
+func (s Systems[W]) Run(w W) {
+	for _, system := range s {
+		system(w)
+	}
+}
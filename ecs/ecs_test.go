@@ -0,0 +1,70 @@
+package ecs
+
+import "testing"
+
+func TestRegistryCreateDestroy(t *testing.T) {
+	var r = NewRegistry()
+	var bullets = NewStore[int](r)
+	var a, b = r.Create(), r.Create()
+	if a == b {
+		t.Fatalf("Create returned %d twice", a)
+	}
+	bullets.Add(a, 1)
+	bullets.Add(b, 2)
+
+	r.Destroy(a)
+	if r.Alive(a) || !r.Alive(b) {
+		t.Errorf("after destroying %d: Alive(%d) = %v, Alive(%d) = %v", a, a, r.Alive(a), b, r.Alive(b))
+	}
+	if bullets.Has(a) || bullets.Len() != 1 {
+		t.Errorf("the store still holds the destroyed entity")
+	}
+	r.Destroy(a)
+	if r.Count() != 1 || bullets.Len() != 1 {
+		t.Errorf("destroying twice changed the count to %d", r.Count())
+	}
+
+	var c = r.Create()
+	if c == a || c == b {
+		t.Errorf("Create reused %d", c)
+	}
+	if bullets.Has(c) {
+		t.Errorf("a new entity starts with the old one's component")
+	}
+	if r.Count() != 2 {
+		t.Errorf("Count() = %d, want 2", r.Count())
+	}
+}
+
+func TestStoreOrder(t *testing.T) {
+	var r = NewRegistry()
+	var store = NewStore[string](r)
+	var entities []Entity
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		var e = r.Create()
+		entities = append(entities, e)
+		store.Add(e, name)
+	}
+	var check = func(want string) {
+		t.Helper()
+		var got string
+		for i, v := range store.Values() {
+			got += v
+			if value, _ := store.Get(store.Entity(i)); *value != v {
+				t.Errorf("entity %d holds %s, want %s", store.Entity(i), *value, v)
+			}
+		}
+		if got != want {
+			t.Errorf("store iterates %s, want %s", got, want)
+		}
+	}
+	check("abcde")
+
+	r.Destroy(entities[1])
+	check("aecd")
+	store.Add(entities[2], "C")
+	check("aeCd")
+	r.Destroy(entities[4])
+	check("adC")
+
+}
@@ -0,0 +1,85 @@
+package ecs
+
+// Store holds one type of component, packed together for fast iteration.
+// Components are kept in the order they were added, except that removing
+// one moves the last component into its place.
+type Store[T any] struct {
+	entities []Entity
+	values   []T
+	index    map[Entity]int
+}
+
+// NewStore creates a store whose components go away with their entity.
+func NewStore[T any](r *Registry) *Store[T] {
+	var s = &Store[T]{index: map[Entity]int{}}
+	r.stores = append(r.stores, s)
+	return s
+}
+
+func (s *Store[T]) Add(e Entity, value T) {
+	if i, ok := s.index[e]; ok {
+		s.values[i] = value
+		return
+	}
+	s.index[e] = len(s.values)
+	s.entities = append(s.entities, e)
+	s.values = append(s.values, value)
+}
+
+func (s *Store[T]) Get(e Entity) (*T, bool) {
+	i, ok := s.index[e]
+	if !ok {
+		return nil, false
+	}
+	return &s.values[i], true
+}
+
+func (s *Store[T]) Has(e Entity) bool {
+	_, ok := s.index[e]
+	return ok
+}
+
+func (s *Store[T]) Remove(e Entity) {
+	i, ok := s.index[e]
+	if !ok {
+		return
+	}
+	var last = len(s.values) - 1
+	s.entities[i], s.values[i] = s.entities[last], s.values[last]
+	s.index[s.entities[i]] = i
+	delete(s.index, e)
+	var zero T
+	s.values[last] = zero
+	s.entities = s.entities[:last]
+	s.values = s.values[:last]
+}
+
+func (s *Store[T]) Len() int {
+	return len(s.values)
+}
+
+// Entity and At give the i-th entity and its component, for loops that
+// remove entities as they go.
+func (s *Store[T]) Entity(i int) Entity {
+	return s.entities[i]
+}
+
+func (s *Store[T]) At(i int) *T {
+	return &s.values[i]
+}
+
+// Values returns the components in order. The slice belongs to the store
+// and is only good until the next change to it.
+func (s *Store[T]) Values() []T {
+	return s.values
+}
+
+// CopyFrom makes s hold the same components as src, reusing s's memory.
+func (s *Store[T]) CopyFrom(src *Store[T]) {
+	s.entities = append(s.entities[:0], src.entities...)
+	s.values = append(s.values[:0], src.values...)
+	clear(s.index)
+	for i, e := range s.entities {
+		s.index[e] = i
+	}
+}
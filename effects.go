@@ -0,0 +1,140 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/akshayxml/spaders/events"
+	"github.com/akshayxml/spaders/models"
+	"github.com/akshayxml/spaders/models/Screen"
+	"github.com/akshayxml/spaders/particles"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/theme"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// The colors of the bursts come from the palette when they go off.
+var (
+	enemyBurst = particles.Emitter{
+		Count: 24, Spread: math.Pi, MinSpeed: 0.5, MaxSpeed: 2.5,
+		Lifetime: 30, LifetimeJitter: 10, Gravity: 0.02, Size: 2,
+	}
+	bunkerDebris = particles.Emitter{
+		Count: 10, Spread: math.Pi, MinSpeed: 0.3, MaxSpeed: 1.5,
+		Lifetime: 25, LifetimeJitter: 8, Gravity: 0.08, Size: 2,
+	}
+	playerExplosion = particles.Emitter{
+		Count: 60, Spread: math.Pi, MinSpeed: 0.5, MaxSpeed: 3,
+		Lifetime: 60, LifetimeJitter: 20, Gravity: 0.05, Size: 3,
+	}
+	bulletSparks = particles.Emitter{
+		Count: 12, Spread: math.Pi, MinSpeed: 1, MaxSpeed: 3,
+		Lifetime: 12, LifetimeJitter: 4, Size: 1.5,
+	}
+)
+
+type effectsState struct {
+	particles *particles.System
+	popups    []popup
+	// The world the particles belong to. They are thrown away when another
+	// world comes on screen.
+	world *simulation.World
+}
+
+// How long the points scored float above where they were scored, in ticks,
+// and how far they rise each tick.
+const (
+	popupTicks = 40
+	popupRise  = 0.5
+)
+
+type popup struct {
+	text     string
+	position models.Position
+	age      int
+}
+
+// newEffects subscribes the particles and the points that float up from
+// kills to the events that set them off.
+func newEffects(bus *events.Bus, palette func() theme.Palette) *effectsState {
+	var effects = &effectsState{}
+	var system = particles.NewSystem(time.Now().UnixNano())
+	// emit starts a burst that fades from one color to another as it
+	// disappears.
+	var emit = func(e particles.Emitter, from, to color.RGBA, at models.Position) {
+		e.From = from
+		e.To = color.RGBA{to.R, to.G, to.B, 0}
+		system.Emit(e, at.X, at.Y)
+	}
+	events.Subscribe(bus, func(e events.EnemyKilled) {
+		emit(enemyBurst, palette().Enemy, palette().Explosion, e.Position)
+		effects.popups = append(effects.popups, popup{text: "+" + strconv.Itoa(e.Points), position: e.Position})
+	})
+	events.Subscribe(bus, func(e events.BunkerHit) {
+		emit(bunkerDebris, palette().Bunker, palette().Bunker, e.Position)
+	})
+	events.Subscribe(bus, func(e events.PlayerHit) {
+		emit(playerExplosion, palette().Text, palette().Player, e.Position)
+	})
+	events.Subscribe(bus, func(e events.BulletsCollided) {
+		emit(bulletSparks, palette().PlayerBullet, palette().Spark, e.Position)
+		effects.popups = append(effects.popups, popup{text: "+" + strconv.Itoa(e.Points), position: e.Position})
+	})
+	effects.particles = system
+	return effects
+}
+
+// updateEffects moves the particles along. Events are published as each
+// tick is stepped, since an update can step several ticks or none.
+// Switching to another world, like a new game or another player's world
+// when spectating, throws away the old world's particles.
+func (g *Game) updateEffects() {
+	if g.paused && g.screen == Screen.Play {
+		return
+	}
+	var e = g.effects
+	if g.world != e.world {
+		e.particles.Clear()
+		e.popups = e.popups[:0]
+		e.world = g.world
+	}
+	e.particles.Update()
+	e.updatePopups()
+}
+
+// keepEffects lets the particles carry over to the world now on screen,
+// which is how the explosion that ends an alternating turn plays out over
+// the handover.
+func (g *Game) keepEffects() {
+	g.effects.world = g.world
+}
+
+func (e *effectsState) updatePopups() {
+	var kept = e.popups[:0]
+	for _, p := range e.popups {
+		p.age++
+		p.position.Y -= popupRise
+		if p.age < popupTicks {
+			kept = append(kept, p)
+		}
+	}
+	e.popups = kept
+}
+
+// drawPopups draws the points fading out as they rise.
+func (g *Game) drawPopups(screen *ebiten.Image, palette theme.Palette) {
+	for _, p := range g.effects.popups {
+		textOp := &text.DrawOptions{}
+		textOp.GeoM.Translate(p.position.X, p.position.Y)
+		textOp.PrimaryAlign = text.AlignCenter
+		textOp.ColorScale.ScaleWithColor(palette.Accent)
+		textOp.ColorScale.ScaleAlpha(1 - float32(p.age)/popupTicks)
+		text.Draw(screen, p.text, &text.GoTextFace{
+			Source: mplusFaceSource,
+			Size:   normalFontSize / 2,
+		}, textOp)
+	}
+}
@@ -0,0 +1,34 @@
+package events
+
+import "reflect"
+
+// Bus hands every event published on it to the handlers subscribed to its
+// type. Handlers run straight away, in the order they subscribed.
+type Bus struct {
+	handlers map[reflect.Type][]func(Event)
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: map[reflect.Type][]func(Event){}}
+}
+
+// Subscribe calls handler with every event of type E published on b.
+func Subscribe[E any](b *Bus, handler func(E)) {
+	var t = reflect.TypeOf((*E)(nil)).Elem()
+	b.handlers[t] = append(b.handlers[t], func(e Event) {
+		handler(e.(E))
+	})
+}
+
+func (b *Bus) Publish(e Event) {
+	for _, handler := range b.handlers[reflect.TypeOf(e)] {
+		handler(e)
+	}
+}
+
+// PublishAll publishes events in order, like the events of a tick.
+func (b *Bus) PublishAll(events []Event) {
+	for _, e := range events {
+		b.Publish(e)
+	}
+}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestBusDeliversByType(t *testing.T) {
+	var bus = NewBus()
+	var got []string
+	Subscribe(bus, func(e EnemyKilled) {
+		got = append(got, fmt.Sprint("first kill ", e.Points))
+	})
+	Subscribe(bus, func(e PlayerHit) {
+		got = append(got, fmt.Sprint("hit ", e.Lives))
+	})
+	Subscribe(bus, func(e EnemyKilled) {
+		got = append(got, fmt.Sprint("second kill ", e.Points))
+	})
+	Subscribe(bus, func(WaveCleared) {
+		t.Error("a WaveCleared handler got called without one being published")
+	})
+
+	bus.PublishAll([]Event{
+		EnemyKilled{Points: 10},
+		PlayerHit{Lives: 2},
+		BunkerHit{},
+		EnemyKilled{Points: 30},
+	})
+	var want = []string{
+		"first kill 10", "second kill 10",
+		"hit 2",
+		"first kill 30", "second kill 30",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("handlers ran %q, want %q", got, want)
+	}
+}
+
+func TestBusMatchesExactType(t *testing.T) {
+	var bus = NewBus()
+	Subscribe(bus, func(GameOver) {
+		t.Error("a GameOver handler got a *GameOver")
+	})
+	bus.Publish(&GameOver{})
+}
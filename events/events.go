@@ -0,0 +1,56 @@
+// Package events describes what happens in a game, so that sound, effects,
+// the HUD and statistics can react to it without being wired into the
+// rules. The simulation records the events of each tick on the world, and a
+// Bus hands them to whoever subscribed.
+package events
+
+import (
+	"github.com/akshayxml/spaders/models"
+	"github.com/akshayxml/spaders/models/EnemyType"
+)
+
+// Event is one of the event types below.
+type Event any
+
+// EnemyKilled is sent when a player's bullet destroys an invader.
+type EnemyKilled struct {
+	Player   int
+	Enemy    int
+	Type     EnemyType.EnemyType
+	Position models.Position
+	Points   int
+}
+
+// PlayerHit is sent when a cannon loses a life, shot by an invader or
+// reached by the formation. Lives is what it has left.
+type PlayerHit struct {
+	Player   int
+	Position models.Position
+	Lives    int
+	Invaded  bool
+}
+
+// BunkerHit is sent when a bullet from either side chips a bunker.
+type BunkerHit struct {
+	Bunker   int
+	Position models.Position
+}
+
+// BulletsCollided is sent when a player shoots down an enemy bullet.
+type BulletsCollided struct {
+	Player   int
+	Position models.Position
+	Points   int
+}
+
+// WaveCleared is sent when the last invader of the formation is killed.
+type WaveCleared struct {
+	Score int
+}
+
+// GameOver is sent on the tick after which the world can't be played any
+// more, whether the formation was cleared or every cannon was lost.
+type GameOver struct {
+	Score   int
+	Cleared bool
+}
@@ -2,7 +2,10 @@ module github.com/akshayxml/spaders
 
 go 1.21.6
 
-require github.com/hajimehoshi/ebiten/v2 v2.7.6
+require (
+	github.com/hajimehoshi/ebiten/v2 v2.7.6
+	golang.org/x/image v0.18.0
+)
 
 require (
 	github.com/ebitengine/gomobile v0.0.0-20240518074828-e86332849895 // indirect
@@ -15,7 +18,6 @@ require (
 	github.com/hajimehoshi/go-mp3 v0.3.4 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
 	golang.org/x/exp v0.0.0-20190731235908-ec7cb31e5a56 // indirect
-	golang.org/x/image v0.18.0 // indirect
 	golang.org/x/mobile v0.0.0-20210208171126-f462b3930c8f // indirect
 	golang.org/x/sync v0.7.0 // indirect
 	golang.org/x/sys v0.20.0 // indirect
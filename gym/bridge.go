@@ -0,0 +1,124 @@
+package gym
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+)
+
+// Request is one line sent to the bridge. Cmd is "spec", "reset" or "step".
+type Request struct {
+	Cmd    string `json:"cmd"`
+	Seed   int64  `json:"seed,omitempty"`
+	Action Action `json:"action,omitempty"`
+}
+
+// Response answers a request on a line of its own. Only the fields that
+// apply to the command are set; Error is set instead when it failed.
+type Response struct {
+	Error       string       `json:"error,omitempty"`
+	Observation *Observation `json:"observation,omitempty"`
+	Reward      float64      `json:"reward"`
+	Done        bool         `json:"done"`
+	Info        *Info        `json:"info,omitempty"`
+	Spec        *Spec        `json:"spec,omitempty"`
+}
+
+// Spec tells an agent what the environment looks like before it starts.
+type Spec struct {
+	Actions     []string `json:"actions"`
+	Features    []string `json:"features,omitempty"`
+	PixelWidth  int      `json:"pixelWidth,omitempty"`
+	PixelHeight int      `json:"pixelHeight,omitempty"`
+	Difficulty  int      `json:"difficulty"`
+	FrameSkip   int      `json:"frameSkip"`
+	MaxTicks    int64    `json:"maxTicks"`
+}
+
+func (e *Env) Spec() Spec {
+	var spec = Spec{
+		Actions:    ActionNames,
+		Difficulty: e.options.Difficulty,
+		FrameSkip:  e.options.FrameSkip,
+		MaxTicks:   e.options.MaxTicks,
+	}
+	if e.options.Features {
+		spec.Features = FeatureNames()
+	}
+	if e.options.Pixels {
+		spec.PixelWidth, spec.PixelHeight = pixelSize(e.options.Scale)
+	}
+	return spec
+}
+
+func (e *Env) handle(req Request) Response {
+	switch req.Cmd {
+	case "spec":
+		var spec = e.Spec()
+		return Response{Spec: &spec}
+	case "reset":
+		var obs = e.Reset(req.Seed)
+		var info = e.info()
+		return Response{Observation: &obs, Info: &info}
+	case "step":
+		if req.Action < 0 || int(req.Action) >= len(actionInputs) {
+			return Response{Error: fmt.Sprintf("unknown action %d", req.Action)}
+		}
+		if e.world == nil {
+			return Response{Error: "reset before stepping"}
+		}
+		obs, reward, done, info := e.Step(req.Action)
+		return Response{Observation: &obs, Reward: reward, Done: done, Info: &info}
+	}
+	return Response{Error: fmt.Sprintf("unknown command %q", req.Cmd)}
+}
+
+// Serve answers JSON requests read from r, one per line, with responses
+// written to w until r runs out. Each call has an environment of its own.
+func Serve(r io.Reader, w io.Writer, options Options) error {
+	var env = NewEnv(options)
+	var decoder = json.NewDecoder(r)
+	var encoder = json.NewEncoder(w)
+	for {
+		var req Request
+		if err := decoder.Decode(&req); err == io.EOF {
+			return nil
+		} else if err != nil {
+			encoder.Encode(Response{Error: "bad request: " + err.Error()})
+			// A field of the wrong type can be skipped, but the stream
+			// can't be picked up again after broken JSON.
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				continue
+			}
+			return err
+		}
+		if err := encoder.Encode(env.handle(req)); err != nil {
+			return err
+		}
+	}
+}
+
+// ListenAndServe serves every connection made to addr with Serve.
+func ListenAndServe(addr string, options Options) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			if err := Serve(conn, conn, options); err != nil {
+				log.Printf("gym: %v: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
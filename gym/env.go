@@ -0,0 +1,158 @@
+// Package gym wraps the headless game in the reset/step interface that
+// reinforcement learning agents expect.
+package gym
+
+import (
+	"github.com/akshayxml/spaders/simulation"
+)
+
+// Action is one of a small set of discrete moves.
+type Action int
+
+const (
+	Noop Action = iota
+	Left
+	Right
+	Fire
+	LeftFire
+	RightFire
+)
+
+var actionInputs = []simulation.Input{
+	Noop:      0,
+	Left:      simulation.InputLeft,
+	Right:     simulation.InputRight,
+	Fire:      simulation.InputFire,
+	LeftFire:  simulation.InputLeft | simulation.InputFire,
+	RightFire: simulation.InputRight | simulation.InputFire,
+}
+
+var ActionNames = []string{"NOOP", "LEFT", "RIGHT", "FIRE", "LEFTFIRE", "RIGHTFIRE"}
+
+const (
+	DefaultScale    = 8
+	DefaultMaxTicks = 10 * 60 * simulation.TicksPerSecond
+)
+
+type Options struct {
+	Difficulty int
+	// Which observations to produce. With neither set only features are.
+	Pixels   bool
+	Features bool
+	// The pixel buffer is the screen shrunk by this factor.
+	Scale int
+	// Each action is held for this many ticks.
+	FrameSkip int
+	// Episodes still going after this many ticks are cut short.
+	MaxTicks int64
+	// Taken off the reward whenever a life is lost.
+	LifePenalty float64
+}
+
+func (o Options) withDefaults() Options {
+	if o.Difficulty < 1 || o.Difficulty > 3 {
+		o.Difficulty = 1
+	}
+	if !o.Pixels && !o.Features {
+		o.Features = true
+	}
+	if o.Scale < 1 {
+		o.Scale = DefaultScale
+	}
+	if o.FrameSkip < 1 {
+		o.FrameSkip = 1
+	}
+	if o.MaxTicks <= 0 {
+		o.MaxTicks = DefaultMaxTicks
+	}
+	return o
+}
+
+// Observation holds whichever of the two observations the environment was
+// asked for. Pixels is a grayscale image, one byte per pixel, row by row.
+type Observation struct {
+	Features []float64 `json:"features,omitempty"`
+	Pixels   []byte    `json:"pixels,omitempty"`
+	Width    int       `json:"width,omitempty"`
+	Height   int       `json:"height,omitempty"`
+}
+
+type Info struct {
+	Score int   `json:"score"`
+	Lives int   `json:"lives"`
+	Tick  int64 `json:"tick"`
+	Won   bool  `json:"won"`
+	// The episode hit MaxTicks rather than ending on its own.
+	Truncated bool `json:"truncated"`
+}
+
+// Env is a single player game driven one action at a time.
+type Env struct {
+	options Options
+	world   *simulation.World
+	done    bool
+}
+
+func NewEnv(options Options) *Env {
+	return &Env{options: options.withDefaults()}
+}
+
+func (e *Env) Options() Options {
+	return e.options
+}
+
+// Reset starts a new episode. The same seed always plays out the same way
+// given the same actions.
+func (e *Env) Reset(seed int64) Observation {
+	e.world = simulation.New(seed, e.options.Difficulty, 1)
+	e.done = false
+	return e.observe()
+}
+
+// Step plays action for FrameSkip ticks. The reward is the score gained,
+// less LifePenalty for every life lost. Stepping a finished episode, or one
+// that was never reset, does nothing and reports it as done.
+func (e *Env) Step(action Action) (Observation, float64, bool, Info) {
+	if e.world == nil {
+		e.Reset(0)
+		e.done = true
+	}
+	if e.done {
+		return e.observe(), 0, true, e.info()
+	}
+
+	var in simulation.Input
+	if action >= 0 && int(action) < len(actionInputs) {
+		in = actionInputs[action]
+	}
+	var player = e.world.Players[0]
+	var score, lives = e.world.Score, player.Lives
+	for i := 0; i < e.options.FrameSkip && e.world.CanPlay(); i++ {
+		e.world.Step([]simulation.Input{in})
+	}
+
+	var reward = float64(e.world.Score-score) - e.options.LifePenalty*float64(lives-player.Lives)
+	e.done = !e.world.CanPlay() || e.world.Tick >= e.options.MaxTicks
+	return e.observe(), reward, e.done, e.info()
+}
+
+func (e *Env) info() Info {
+	return Info{
+		Score:     e.world.Score,
+		Lives:     e.world.Players[0].Lives,
+		Tick:      e.world.Tick,
+		Won:       e.world.EnemyState.EnemyCount == 0,
+		Truncated: e.world.CanPlay() && e.world.Tick >= e.options.MaxTicks,
+	}
+}
+
+func (e *Env) observe() Observation {
+	var obs Observation
+	if e.options.Features {
+		obs.Features = Features(e.world)
+	}
+	if e.options.Pixels {
+		obs.Pixels, obs.Width, obs.Height = Pixels(e.world, e.options.Scale)
+	}
+	return obs
+}
@@ -0,0 +1,158 @@
+package gym
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/akshayxml/spaders/models"
+	"github.com/akshayxml/spaders/models/EntityState"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/sprites"
+)
+
+const (
+	// How many enemy bullets, closest to the cannon first, are described.
+	nearestBullets = 5
+	maxLives       = 3
+)
+
+// Shades of gray each kind of object is drawn with in the pixel buffer.
+const (
+	bunkerShade      = 80
+	enemyShade       = 160
+	enemyBulletShade = 210
+	playerShade      = 255
+)
+
+var enemyCount = len(simulation.New(0, 1, 1).Enemies)
+
+// FeatureNames describes every entry of the feature vector, in order.
+// Positions are scaled to roughly 0..1 by the size of the screen.
+func FeatureNames() []string {
+	var names = []string{
+		"player_x", "player_bullet_active", "player_bullet_x", "player_bullet_y", "lives",
+		"formation_direction", "formation_speed", "formation_left", "formation_right", "formation_bottom",
+	}
+	for i := 0; i < nearestBullets; i++ {
+		var prefix = "bullet_" + strconv.Itoa(i) + "_"
+		names = append(names, prefix+"present", prefix+"dx", prefix+"dy")
+	}
+	for i := 0; i < enemyCount; i++ {
+		names = append(names, "enemy_"+strconv.Itoa(i)+"_alive")
+	}
+	return names
+}
+
+// Features describes the world as a fixed length vector laid out as in
+// FeatureNames: the cannon, the formation as a whole, the enemy bullets
+// closest to the cannon relative to it, and which invaders of the grid are
+// still alive.
+func Features(w *simulation.World) []float64 {
+	var player = w.Players[0]
+	var features = make([]float64, 0, len(FeatureNames()))
+	features = append(features,
+		player.Position.X/simulation.Width,
+		boolFeature(player.Bullet.IsActive),
+		player.Bullet.Position.X/simulation.Width,
+		player.Bullet.Position.Y/simulation.Height,
+		float64(player.Lives)/maxLives,
+	)
+
+	var left, right, bottom = simulation.Width, 0.0, 0.0
+	for _, enemy := range w.Enemies {
+		if enemy.State == EntityState.Alive {
+			left = min(left, enemy.Position.X)
+			right = max(right, enemy.Position.X+enemy.GetEnemyWidth())
+			bottom = max(bottom, enemy.Position.Y+enemy.GetEnemyHeight())
+		}
+	}
+	features = append(features,
+		float64(w.EnemyState.HorizontalDirection),
+		w.EnemyState.HorizontalSpeed,
+		left/simulation.Width,
+		right/simulation.Width,
+		bottom/simulation.Height,
+	)
+
+	var bullets = append([]models.Bullet{}, w.EnemyBullets.Values()...)
+	var distance = func(b models.Bullet) float64 {
+		return math.Hypot(b.Position.X-player.Position.X, b.Position.Y-player.Position.Y)
+	}
+	sort.SliceStable(bullets, func(i, j int) bool {
+		return distance(bullets[i]) < distance(bullets[j])
+	})
+	for i := 0; i < nearestBullets; i++ {
+		if i >= len(bullets) {
+			features = append(features, 0, 0, 0)
+			continue
+		}
+		features = append(features, 1,
+			(bullets[i].Position.X-player.Position.X)/simulation.Width,
+			(player.Position.Y-bullets[i].Position.Y)/simulation.Height,
+		)
+	}
+
+	for _, enemy := range w.Enemies {
+		features = append(features, boolFeature(enemy.State == EntityState.Alive))
+	}
+	return features
+}
+
+func boolFeature(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Pixels draws the world into a grayscale buffer the size of the screen
+// divided by scale. Invaders are drawn as solid boxes.
+func Pixels(w *simulation.World, scale int) ([]byte, int, int) {
+	var width, height = pixelSize(scale)
+	var pixels = make([]byte, width*height)
+	var fill = func(x, y, rectWidth, rectHeight float64, shade byte) {
+		var s = float64(scale)
+		var x0, y0 = max(int(x/s), 0), max(int(y/s), 0)
+		var x1, y1 = min(int(math.Ceil((x+rectWidth)/s)), width), min(int(math.Ceil((y+rectHeight)/s)), height)
+		for row := y0; row < y1; row++ {
+			for col := x0; col < x1; col++ {
+				pixels[row*width+col] = max(pixels[row*width+col], shade)
+			}
+		}
+	}
+
+	for _, sprite := range w.BunkerSprites {
+		if sprite.Height > 0 {
+			fill(sprite.Position.X, sprite.Position.Y, sprite.Width, sprite.Height, bunkerShade)
+		}
+	}
+	for _, enemy := range w.Enemies {
+		if enemy.State == EntityState.Alive {
+			fill(enemy.Position.X, enemy.Position.Y, enemy.GetEnemyWidth(), enemy.GetEnemyHeight(), enemyShade)
+		}
+	}
+	for _, bullet := range w.EnemyBullets.Values() {
+		for _, rect := range sprites.GetEnemyBulletRectangles() {
+			fill(bullet.Position.X+rect.Position.X, bullet.Position.Y+rect.Position.Y, rect.Width, rect.Height, enemyBulletShade)
+		}
+	}
+	for _, player := range w.Players {
+		if player.Lives == 0 {
+			continue
+		}
+		for _, rect := range sprites.GetPlayerRectangles() {
+			fill(player.Position.X+rect.Position.X, player.Position.Y+rect.Position.Y, rect.Width, rect.Height, playerShade)
+		}
+		if player.Bullet.IsActive {
+			for _, rect := range sprites.GetPlayerBulletRectangles() {
+				fill(player.Bullet.Position.X+rect.Position.X, player.Bullet.Position.Y+rect.Position.Y, rect.Width, rect.Height, playerShade)
+			}
+		}
+	}
+	return pixels, width, height
+}
+
+func pixelSize(scale int) (int, int) {
+	return int(simulation.Width) / scale, int(simulation.Height) / scale
+}
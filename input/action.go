@@ -0,0 +1,102 @@
+package input
+
+import "fmt"
+
+type Action int
+
+const (
+	MoveLeft Action = iota
+	MoveRight
+	Fire
+	Pause
+	MenuUp
+	MenuDown
+	Confirm
+	Back
+	Autopilot
+	Screenshot
+	Record
+	Fullscreen
+	Debug
+	SlowMotion
+	FrameStep
+)
+
+var Actions = []Action{
+	MoveLeft, MoveRight, Fire, Pause, MenuUp, MenuDown, Confirm, Back, Autopilot,
+	Screenshot, Record, Fullscreen, Debug, SlowMotion, FrameStep,
+}
+
+var actionNames = map[Action]string{
+	MoveLeft:   "MoveLeft",
+	MoveRight:  "MoveRight",
+	Fire:       "Fire",
+	Pause:      "Pause",
+	MenuUp:     "MenuUp",
+	MenuDown:   "MenuDown",
+	Confirm:    "Confirm",
+	Back:       "Back",
+	Autopilot:  "Autopilot",
+	Screenshot: "Screenshot",
+	Record:     "Record",
+	Fullscreen: "Fullscreen",
+	Debug:      "Debug",
+	SlowMotion: "SlowMotion",
+	FrameStep:  "FrameStep",
+}
+
+// Where each action is used. Actions used on the same screens can't share a
+// key, but Fire and Confirm can both be Space, since one is only used while
+// playing and the other only in menus.
+type scope int
+
+const (
+	playScope scope = 1 << iota
+	menuScope
+)
+
+var actionScopes = map[Action]scope{
+	MoveLeft:   playScope | menuScope,
+	MoveRight:  playScope | menuScope,
+	Fire:       playScope,
+	Pause:      playScope,
+	MenuUp:     menuScope,
+	MenuDown:   menuScope,
+	Confirm:    menuScope,
+	Back:       playScope | menuScope,
+	Autopilot:  playScope,
+	Screenshot: playScope | menuScope,
+	Record:     playScope | menuScope,
+	Fullscreen: playScope | menuScope,
+	Debug:      playScope | menuScope,
+	SlowMotion: playScope | menuScope,
+	FrameStep:  playScope | menuScope,
+}
+
+// Clashes reports whether a and b are used on the same screen, so that they
+// can't be bound to the same key.
+func (a Action) Clashes(b Action) bool {
+	return a != b && actionScopes[a]&actionScopes[b] != 0
+}
+
+func (a Action) String() string {
+	return actionNames[a]
+}
+
+func (a Action) MarshalText() ([]byte, error) {
+	name, ok := actionNames[a]
+	if !ok {
+		return nil, fmt.Errorf("input: unknown action %d", int(a))
+	}
+	return []byte(name), nil
+}
+
+func (a *Action) UnmarshalText(text []byte) error {
+	for action, name := range actionNames {
+		if name == string(text) {
+			*a = action
+			return nil
+		}
+	}
+	return fmt.Errorf("input: unknown action %q", string(text))
+}
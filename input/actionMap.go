@@ -0,0 +1,186 @@
+package input
+
+import (
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const MaxKeysPerAction = 2
+
+type Bindings map[Action][]ebiten.Key
+
+func DefaultBindings() Bindings {
+	return Bindings{
+		MoveLeft:   {ebiten.KeyArrowLeft, ebiten.KeyA},
+		MoveRight:  {ebiten.KeyArrowRight, ebiten.KeyD},
+		Fire:       {ebiten.KeySpace},
+		Pause:      {ebiten.KeyP},
+		MenuUp:     {ebiten.KeyArrowUp, ebiten.KeyW},
+		MenuDown:   {ebiten.KeyArrowDown, ebiten.KeyS},
+		Confirm:    {ebiten.KeySpace, ebiten.KeyEnter},
+		Back:       {ebiten.KeyEscape},
+		Autopilot:  {ebiten.KeyF2},
+		Screenshot: {ebiten.KeyF12},
+		Record:     {ebiten.KeyF10},
+		Fullscreen: {ebiten.KeyF11},
+		Debug:      {ebiten.KeyF3},
+		SlowMotion: {ebiten.KeyF4},
+		FrameStep:  {ebiten.KeyF5},
+	}
+}
+
+// DefaultPlayer2Bindings only covers what the second cannon needs in co-op;
+// menus are always driven by the first player.
+func DefaultPlayer2Bindings() Bindings {
+	return Bindings{
+		MoveLeft:  {ebiten.KeyJ},
+		MoveRight: {ebiten.KeyL},
+		Fire:      {ebiten.KeyK},
+	}
+}
+
+func (b Bindings) clone() Bindings {
+	var clone = Bindings{}
+	for action, keys := range b {
+		clone[action] = slices.Clone(keys)
+	}
+	return clone
+}
+
+type ActionMap struct {
+	bindings Bindings
+	defaults Bindings
+	gamepads *gamepads
+	pointers *Pointers
+	// The map of the other player at the same keyboard, whose keys can't be
+	// taken while playing.
+	partner *ActionMap
+}
+
+func NewActionMap(bindings Bindings, defaults Bindings) *ActionMap {
+	var m = &ActionMap{bindings: Bindings{}, defaults: defaults.clone(), gamepads: newGamepads(), pointers: newPointers()}
+	for _, action := range Actions {
+		if keys, ok := bindings[action]; ok && len(keys) > 0 {
+			m.bindings[action] = slices.Clone(keys)
+		} else {
+			m.bindings[action] = slices.Clone(defaults[action])
+		}
+	}
+	return m
+}
+
+// SetPartner makes m and partner the maps of two players sharing the
+// keyboard, so that neither can bind a key the other plays with.
+func (m *ActionMap) SetPartner(partner *ActionMap) {
+	m.partner = partner
+	partner.partner = m
+}
+
+// Update must be called once per tick before any action is queried.
+func (m *ActionMap) Update() {
+	m.gamepads.update()
+	m.pointers.update()
+}
+
+func (m *ActionMap) IsPressed(action Action) bool {
+	for _, key := range m.bindings[action] {
+		if ebiten.IsKeyPressed(key) {
+			return true
+		}
+	}
+	return m.gamepads.isPressed(action)
+}
+
+func (m *ActionMap) IsJustPressed(action Action) bool {
+	for _, key := range m.bindings[action] {
+		if inpututil.IsKeyJustPressed(key) {
+			return true
+		}
+	}
+	return m.gamepads.isJustPressed(action)
+}
+
+func (m *ActionMap) Pointers() *Pointers {
+	return m.pointers
+}
+
+func (m *ActionMap) Gamepads() []ebiten.GamepadID {
+	return m.gamepads.ids
+}
+
+// SetGamepadIndex restricts the map to the n-th connected gamepad, or to
+// AllGamepads or NoGamepad.
+func (m *ActionMap) SetGamepadIndex(n int) {
+	m.gamepads.index = n
+}
+
+func (m *ActionMap) DeadZone() float64 {
+	return m.gamepads.deadZone
+}
+
+func (m *ActionMap) SetDeadZone(deadZone float64) {
+	m.gamepads.deadZone = min(max(deadZone, MinDeadZone), MaxDeadZone)
+}
+
+func (m *ActionMap) Keys(action Action) []ebiten.Key {
+	return m.bindings[action]
+}
+
+// Clash is the action that already uses a key. Partner is set when the
+// action belongs to the other player.
+type Clash struct {
+	Action  Action
+	Partner bool
+}
+
+// Bind makes key the primary binding of action, keeping the previous
+// primary as a secondary binding. When an action that clashes with it
+// already uses key, nothing changes and that action is returned. Actions
+// used while playing also clash with every key of the partner.
+func (m *ActionMap) Bind(action Action, key ebiten.Key) (Clash, bool) {
+	for _, other := range Actions {
+		if action.Clashes(other) && slices.Contains(m.bindings[other], key) {
+			return Clash{Action: other}, false
+		}
+	}
+	if m.partner != nil && actionScopes[action]&playScope != 0 {
+		for _, other := range Actions {
+			if slices.Contains(m.partner.bindings[other], key) {
+				return Clash{Action: other, Partner: true}, false
+			}
+		}
+	}
+	var keys = []ebiten.Key{key}
+	for _, k := range m.bindings[action] {
+		if k != key && len(keys) < MaxKeysPerAction {
+			keys = append(keys, k)
+		}
+	}
+	m.bindings[action] = keys
+	return Clash{}, true
+}
+
+func (m *ActionMap) Reset() {
+	m.bindings = m.defaults.clone()
+	m.gamepads.deadZone = DefaultDeadZone
+}
+
+func (m *ActionMap) Bindings() Bindings {
+	return m.bindings.clone()
+}
+
+// IsAnyJustPressed reports whether any key or bound gamepad button went down
+// this tick, or a pointer is touching the screen.
+func (m *ActionMap) IsAnyJustPressed() bool {
+	if len(inpututil.AppendJustPressedKeys(nil)) > 0 || len(m.pointers.held) > 0 || len(m.pointers.taps) > 0 {
+		return true
+	}
+	for _, action := range Actions {
+		if m.gamepads.isJustPressed(action) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestBindClashesWithPartner(t *testing.T) {
+	var player1 = NewActionMap(nil, DefaultBindings())
+	var player2 = NewActionMap(nil, DefaultPlayer2Bindings())
+	player1.SetPartner(player2)
+
+	clash, ok := player1.Bind(Fire, ebiten.KeyK)
+	if ok || clash != (Clash{Action: Fire, Partner: true}) {
+		t.Errorf("Bind(Fire, K) = %v, %v, want player 2's Fire", clash, ok)
+	}
+	clash, ok = player2.Bind(MoveLeft, ebiten.KeyA)
+	if ok || clash != (Clash{Action: MoveLeft, Partner: true}) {
+		t.Errorf("Bind(MoveLeft, A) for player 2 = %v, %v, want player 1's MoveLeft", clash, ok)
+	}
+	// Menus are only driven by the first player.
+	if clash, ok = player1.Bind(MenuUp, ebiten.KeyJ); !ok {
+		t.Errorf("Bind(MenuUp, J) clashed with %v", clash)
+	}
+	if clash, ok = player1.Bind(Fire, ebiten.KeyX); !ok {
+		t.Errorf("Bind(Fire, X) clashed with %v", clash)
+	}
+}
+
+func TestBindClashesWithinMap(t *testing.T) {
+	var m = NewActionMap(nil, DefaultBindings())
+	if clash, ok := m.Bind(Pause, ebiten.KeySpace); ok || clash.Action != Fire || clash.Partner {
+		t.Errorf("Bind(Pause, Space) = %v, %v, want Fire", clash, ok)
+	}
+	// Fire and Confirm are never used on the same screen.
+	if clash, ok := m.Bind(Fire, ebiten.KeyEnter); !ok {
+		t.Errorf("Bind(Fire, Enter) clashed with %v", clash)
+	}
+}
+
+func TestDefaultsAreCopied(t *testing.T) {
+	var defaults = DefaultBindings()
+	var m = NewActionMap(nil, defaults)
+	m.Keys(Fire)[0] = ebiten.KeyZ
+	if defaults[Fire][0] != ebiten.KeySpace {
+		t.Error("changing a binding of the map changed the defaults passed in")
+	}
+	m.Reset()
+	m.Keys(Fire)[0] = ebiten.KeyZ
+	m.Reset()
+	if m.Keys(Fire)[0] != ebiten.KeySpace {
+		t.Error("changing a binding after Reset changed the map's defaults")
+	}
+}
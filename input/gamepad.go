@@ -0,0 +1,122 @@
+package input
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	DefaultDeadZone = 0.25
+	MinDeadZone     = 0.05
+	MaxDeadZone     = 0.9
+	AllGamepads     = -1
+	NoGamepad       = -2
+)
+
+type GamepadBindings map[Action][]ebiten.StandardGamepadButton
+
+func DefaultGamepadBindings() GamepadBindings {
+	return GamepadBindings{
+		MoveLeft:  {ebiten.StandardGamepadButtonLeftLeft},
+		MoveRight: {ebiten.StandardGamepadButtonLeftRight},
+		Fire:      {ebiten.StandardGamepadButtonRightBottom},
+		Pause:     {ebiten.StandardGamepadButtonCenterRight},
+		MenuUp:    {ebiten.StandardGamepadButtonLeftTop},
+		MenuDown:  {ebiten.StandardGamepadButtonLeftBottom},
+		Confirm:   {ebiten.StandardGamepadButtonRightBottom, ebiten.StandardGamepadButtonCenterRight},
+		Back:      {ebiten.StandardGamepadButtonRightRight, ebiten.StandardGamepadButtonCenterLeft},
+		Autopilot: {ebiten.StandardGamepadButtonRightTop},
+	}
+}
+
+type gamepads struct {
+	ids         []ebiten.GamepadID
+	index       int
+	bindings    GamepadBindings
+	deadZone    float64
+	stick       map[Action]bool
+	stickBefore map[Action]bool
+}
+
+func newGamepads() *gamepads {
+	return &gamepads{
+		bindings:    DefaultGamepadBindings(),
+		deadZone:    DefaultDeadZone,
+		index:       AllGamepads,
+		stick:       map[Action]bool{},
+		stickBefore: map[Action]bool{},
+	}
+}
+
+func (g *gamepads) active() []ebiten.GamepadID {
+	if g.index == AllGamepads {
+		return g.ids
+	}
+	if g.index < 0 || g.index >= len(g.ids) {
+		return nil
+	}
+	return g.ids[g.index : g.index+1]
+}
+
+// update picks up connected and disconnected gamepads and samples the left
+// stick so that tilting it can be treated like pressing a button.
+func (g *gamepads) update() {
+	g.ids = inpututil.AppendJustConnectedGamepadIDs(g.ids)
+	var connected = g.ids[:0]
+	for _, id := range g.ids {
+		if !inpututil.IsGamepadJustDisconnected(id) {
+			connected = append(connected, id)
+		}
+	}
+	g.ids = connected
+
+	g.stick, g.stickBefore = g.stickBefore, g.stick
+	for _, action := range Actions {
+		g.stick[action] = false
+	}
+	for _, id := range g.active() {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		var x = ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+		var y = ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+		if x <= -g.deadZone {
+			g.stick[MoveLeft] = true
+		} else if x >= g.deadZone {
+			g.stick[MoveRight] = true
+		}
+		if y <= -g.deadZone {
+			g.stick[MenuUp] = true
+		} else if y >= g.deadZone {
+			g.stick[MenuDown] = true
+		}
+	}
+}
+
+func (g *gamepads) isPressed(action Action) bool {
+	if g.stick[action] {
+		return true
+	}
+	for _, id := range g.active() {
+		for _, button := range g.bindings[action] {
+			if ebiten.IsStandardGamepadButtonPressed(id, button) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (g *gamepads) isJustPressed(action Action) bool {
+	if g.stick[action] && !g.stickBefore[action] {
+		return true
+	}
+	for _, id := range g.active() {
+		for _, button := range g.bindings[action] {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package input
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	tapMaxTicks    = 15
+	tapMaxDistance = 10
+	mousePointerID = ebiten.TouchID(-1)
+)
+
+type Point struct {
+	X, Y float64
+}
+
+// View is where the game is drawn in the window: the top left corner and
+// how many window pixels make one logical pixel.
+type View struct {
+	X, Y, Scale float64
+}
+
+type pointer struct {
+	start, current Point
+	ticks          int
+}
+
+// Pointers tracks the mouse and every touch on the screen the same way, so a
+// left click behaves like a single finger.
+type Pointers struct {
+	held     map[ebiten.TouchID]*pointer
+	taps     []Point
+	touchIDs []ebiten.TouchID
+	view     View
+}
+
+func newPointers() *Pointers {
+	return &Pointers{held: map[ebiten.TouchID]*pointer{}, view: View{Scale: 1}}
+}
+
+// SetView sets where the game is in the window, so that positions come out
+// in logical pixels.
+func (p *Pointers) SetView(v View) {
+	p.view = v
+}
+
+func (p *Pointers) point(x, y int) Point {
+	return Point{X: (float64(x) - p.view.X) / p.view.Scale, Y: (float64(y) - p.view.Y) / p.view.Scale}
+}
+
+func (p *Pointers) press(id ebiten.TouchID, x, y int) {
+	var point = p.point(x, y)
+	p.held[id] = &pointer{start: point, current: point}
+}
+
+func (p *Pointers) move(id ebiten.TouchID, x, y int) {
+	if ptr, ok := p.held[id]; ok {
+		ptr.current = p.point(x, y)
+		ptr.ticks++
+	}
+}
+
+func (p *Pointers) release(id ebiten.TouchID) {
+	var ptr, ok = p.held[id]
+	if !ok {
+		return
+	}
+	var distance = math.Hypot(ptr.current.X-ptr.start.X, ptr.current.Y-ptr.start.Y)
+	if ptr.ticks <= tapMaxTicks && distance <= tapMaxDistance {
+		p.taps = append(p.taps, ptr.current)
+	}
+	delete(p.held, id)
+}
+
+func (p *Pointers) update() {
+	p.taps = p.taps[:0]
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		var x, y = ebiten.CursorPosition()
+		p.press(mousePointerID, x, y)
+	}
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		var x, y = ebiten.CursorPosition()
+		p.move(mousePointerID, x, y)
+	}
+	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
+		p.release(mousePointerID)
+	}
+
+	p.touchIDs = inpututil.AppendJustPressedTouchIDs(p.touchIDs[:0])
+	for _, id := range p.touchIDs {
+		var x, y = ebiten.TouchPosition(id)
+		p.press(id, x, y)
+	}
+	p.touchIDs = ebiten.AppendTouchIDs(p.touchIDs[:0])
+	for _, id := range p.touchIDs {
+		var x, y = ebiten.TouchPosition(id)
+		p.move(id, x, y)
+	}
+	p.touchIDs = inpututil.AppendJustReleasedTouchIDs(p.touchIDs[:0])
+	for _, id := range p.touchIDs {
+		p.release(id)
+	}
+}
+
+// Held returns the current position of every pointer that is down.
+func (p *Pointers) Held() []Point {
+	var points = make([]Point, 0, len(p.held))
+	for _, ptr := range p.held {
+		points = append(points, ptr.current)
+	}
+	return points
+}
+
+// Taps returns the pointers released this tick after a short press that
+// barely moved.
+func (p *Pointers) Taps() []Point {
+	return p.taps
+}
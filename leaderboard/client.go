@@ -0,0 +1,82 @@
+package leaderboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akshayxml/spaders/simulation"
+)
+
+const clientTimeout = 10 * time.Second
+
+type Client struct {
+	URL  string
+	http *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		URL:  strings.TrimSuffix(baseURL, "/"),
+		http: &http.Client{Timeout: clientTimeout},
+	}
+}
+
+func (c *Client) do(req *http.Request, wantStatus int, v any) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != wantStatus {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("leaderboard: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (c *Client) Submit(sub Submission) (SubmitResult, error) {
+	var result SubmitResult
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return result, err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.URL+"/scores", bytes.NewReader(data))
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	err = c.do(req, http.StatusCreated, &result)
+	return result, err
+}
+
+func (c *Client) Top(difficulty, n int) ([]Entry, error) {
+	var query = url.Values{}
+	query.Set("difficulty", strconv.Itoa(difficulty))
+	query.Set("limit", strconv.Itoa(n))
+	req, err := http.NewRequest(http.MethodGet, c.URL+"/scores?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	err = c.do(req, http.StatusOK, &entries)
+	return entries, err
+}
+
+func (c *Client) Replay(id string) (*simulation.Replay, error) {
+	req, err := http.NewRequest(http.MethodGet, c.URL+"/replays/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	var replay simulation.Replay
+	if err := c.do(req, http.StatusOK, &replay); err != nil {
+		return nil, err
+	}
+	return &replay, nil
+}
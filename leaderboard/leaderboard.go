@@ -0,0 +1,86 @@
+package leaderboard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akshayxml/spaders/models/GameMode"
+	"github.com/akshayxml/spaders/simulation"
+)
+
+const (
+	DefaultTop    = 10
+	MaxTop        = 100
+	MaxNameLength = 16
+	// Longer replays are rejected so that validating one stays cheap.
+	MaxReplayTicks = 60 * 60 * simulation.TicksPerSecond
+)
+
+var ErrInvalid = errors.New("leaderboard: invalid submission")
+
+type Entry struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Score      int       `json:"score"`
+	Difficulty int       `json:"difficulty"`
+	Ticks      int64     `json:"ticks"`
+	Submitted  time.Time `json:"submitted"`
+}
+
+// Submission is a finished single-player game. The score is only trusted
+// once the replay has been simulated and ends with the same score.
+type Submission struct {
+	Name   string             `json:"name"`
+	Score  int                `json:"score"`
+	Replay *simulation.Replay `json:"replay"`
+}
+
+type SubmitResult struct {
+	Entry Entry `json:"entry"`
+	Rank  int   `json:"rank"`
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalid, fmt.Sprintf(format, args...))
+}
+
+// Validate simulates the replay of sub headlessly and returns the entry it
+// earns.
+func Validate(sub Submission) (Entry, error) {
+	var name = strings.TrimSpace(sub.Name)
+	if name == "" || len(name) > MaxNameLength {
+		return Entry{}, invalid("name must be 1 to %d characters", MaxNameLength)
+	}
+	if sub.Replay == nil {
+		return Entry{}, invalid("missing replay")
+	}
+	var setup = sub.Replay.Setup
+	if setup.Mode != GameMode.Single || len(setup.Seeds) != 1 || setup.Players != 1 {
+		return Entry{}, invalid("only single player games can be submitted")
+	}
+	if setup.Difficulty < 1 || setup.Difficulty > 3 {
+		return Entry{}, invalid("unknown difficulty %d", setup.Difficulty)
+	}
+	worlds, err := sub.Replay.PlayUpTo(MaxReplayTicks)
+	if errors.Is(err, simulation.ErrReplayTooLong) {
+		return Entry{}, invalid("replay is longer than %d ticks", MaxReplayTicks)
+	}
+	if err != nil {
+		return Entry{}, invalid("%v", err)
+	}
+	var world = worlds[0]
+	if world.CanPlay() {
+		return Entry{}, invalid("game is not over")
+	}
+	if world.Score != sub.Score {
+		return Entry{}, invalid("replay scores %d, not %d", world.Score, sub.Score)
+	}
+	return Entry{
+		Name:       name,
+		Score:      world.Score,
+		Difficulty: setup.Difficulty,
+		Ticks:      world.Tick,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package leaderboard
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akshayxml/spaders/models/GameMode"
+	"github.com/akshayxml/spaders/simulation"
+)
+
+// finishedReplay records a single player game on difficulty that fires
+// every other tick without moving until it is over.
+func finishedReplay(t *testing.T, difficulty int) (*simulation.Replay, *simulation.World) {
+	t.Helper()
+	var setup = simulation.Setup{Mode: GameMode.Single, Difficulty: difficulty, Seeds: []int64{4}, Players: 1}
+	var replay = simulation.NewReplay(setup)
+	var w = setup.NewWorlds()[0]
+	for w.CanPlay() {
+		if w.Tick > MaxReplayTicks {
+			t.Fatal("test game never ended")
+		}
+		var frame = simulation.Frame{{0}}
+		if w.Tick%2 == 0 {
+			frame[0][0] = simulation.InputFire
+		}
+		replay.Record(frame)
+		w.Step(frame[0])
+	}
+	return replay, w
+}
+
+func TestValidate(t *testing.T) {
+	var replay, end = finishedReplay(t, 3)
+	if end.Score == 0 {
+		t.Fatal("test game scored nothing, so a forged score can't be told apart")
+	}
+	var with = func(change func(r *simulation.Replay)) *simulation.Replay {
+		var copied = *replay
+		copied.Setup.Seeds = append([]int64{}, replay.Setup.Seeds...)
+		copied.Runs = append([]simulation.Run{}, replay.Runs...)
+		change(&copied)
+		return &copied
+	}
+
+	var tests = []struct {
+		name  string
+		sub   Submission
+		valid bool
+	}{
+		{"valid", Submission{Name: "ACE", Score: end.Score, Replay: replay}, true},
+		{"forged score", Submission{Name: "ACE", Score: end.Score + 5, Replay: replay}, false},
+		{"no name", Submission{Name: "  ", Score: end.Score, Replay: replay}, false},
+		{"no replay", Submission{Name: "ACE", Score: end.Score}, false},
+		{"co-op", Submission{Name: "ACE", Score: end.Score, Replay: with(func(r *simulation.Replay) {
+			r.Setup.Mode, r.Setup.Players = GameMode.Coop, 2
+		})}, false},
+		{"alternating", Submission{Name: "ACE", Score: end.Score, Replay: with(func(r *simulation.Replay) {
+			r.Setup.Mode = GameMode.Alternating
+		})}, false},
+		{"other difficulty", Submission{Name: "ACE", Score: end.Score, Replay: with(func(r *simulation.Replay) {
+			r.Setup.Difficulty = 1
+		})}, false},
+		{"older rules", Submission{Name: "ACE", Score: end.Score, Replay: with(func(r *simulation.Replay) {
+			r.Version = simulation.ReplayVersion - 1
+		})}, false},
+		{"unknown difficulty", Submission{Name: "ACE", Score: end.Score, Replay: with(func(r *simulation.Replay) {
+			r.Setup.Difficulty = 4
+		})}, false},
+		{"not over", Submission{Name: "ACE", Score: end.Score, Replay: with(func(r *simulation.Replay) {
+			r.Runs = r.Runs[:len(r.Runs)/2]
+		})}, false},
+		{"no ticks", Submission{Name: "ACE", Score: 0, Replay: with(func(r *simulation.Replay) {
+			r.Runs = nil
+		})}, false},
+		{"run of zero ticks", Submission{Name: "ACE", Score: end.Score, Replay: with(func(r *simulation.Replay) {
+			r.Runs = append(r.Runs, simulation.Run{Ticks: 0, Frame: simulation.Frame{{0}}})
+		})}, false},
+		{"past the tick cap", Submission{Name: "ACE", Score: end.Score, Replay: with(func(r *simulation.Replay) {
+			r.Runs = append(r.Runs, simulation.Run{Ticks: MaxReplayTicks, Frame: simulation.Frame{{0}}})
+		})}, false},
+	}
+	for _, test := range tests {
+		entry, err := Validate(test.sub)
+		if test.valid {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			} else if entry.Score != end.Score || entry.Ticks != end.Tick || entry.Difficulty != 3 || entry.Name != "ACE" {
+				t.Errorf("%s: entry %+v does not match the game", test.name, entry)
+			}
+		} else if !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: error %v, want ErrInvalid", test.name, err)
+		}
+	}
+}
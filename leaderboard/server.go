@@ -0,0 +1,101 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Replays of an hour of play are well under this.
+const maxSubmissionBytes = 8 << 20
+
+// NewServer serves the leaderboard API:
+//
+//	POST /scores                          submit a Submission
+//	GET  /scores?difficulty=1&limit=10    list the best entries
+//	GET  /replays/{id}                    fetch the replay of an entry
+func NewServer(store *Store) http.Handler {
+	var mux = http.NewServeMux()
+	mux.HandleFunc("/scores", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			listScores(store, w, r)
+		case http.MethodPost:
+			submitScore(store, w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/replays/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		getReplay(store, w, r)
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("leaderboard: writing response: %v", err)
+	}
+}
+
+func listScores(store *Store, w http.ResponseWriter, r *http.Request) {
+	var query = r.URL.Query()
+	difficulty, err := strconv.Atoi(query.Get("difficulty"))
+	if err != nil {
+		http.Error(w, "difficulty is required", http.StatusBadRequest)
+		return
+	}
+	var limit = DefaultTop
+	if query.Has("limit") {
+		limit, err = strconv.Atoi(query.Get("limit"))
+		if err != nil || limit < 1 {
+			http.Error(w, "bad limit", http.StatusBadRequest)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, store.Top(difficulty, min(limit, MaxTop)))
+}
+
+func submitScore(store *Store, w http.ResponseWriter, r *http.Request) {
+	var sub Submission
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSubmissionBytes)).Decode(&sub); err != nil {
+		http.Error(w, "bad submission: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	entry, err := Validate(sub)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	entry, err = store.Add(entry, sub.Replay)
+	if err != nil {
+		log.Printf("leaderboard: storing entry: %v", err)
+		http.Error(w, "could not store entry", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("leaderboard: %s scored %d on difficulty %d", entry.Name, entry.Score, entry.Difficulty)
+	writeJSON(w, http.StatusCreated, SubmitResult{Entry: entry, Rank: store.Rank(entry)})
+}
+
+func getReplay(store *Store, w http.ResponseWriter, r *http.Request) {
+	replay, err := store.Replay(strings.TrimPrefix(r.URL.Path, "/replays/"))
+	if errors.Is(err, ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("leaderboard: reading replay: %v", err)
+		http.Error(w, "could not read replay", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, replay)
+}
@@ -0,0 +1,77 @@
+package leaderboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServerRoundTrip(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var server = httptest.NewServer(NewServer(store))
+	defer server.Close()
+	var client = NewClient(server.URL + "/")
+
+	var replay, end = finishedReplay(t, 3)
+	result, err := client.Submit(Submission{Name: "ACE", Score: end.Score, Replay: replay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rank != 1 || result.Entry.ID == "" || result.Entry.Score != end.Score {
+		t.Errorf("submit returned %+v", result)
+	}
+	if _, err := client.Submit(Submission{Name: "CHEAT", Score: end.Score * 10, Replay: replay}); err == nil || !strings.Contains(err.Error(), "422") {
+		t.Errorf("forged score: error %v, want 422", err)
+	}
+
+	entries, err := client.Top(3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0] != result.Entry {
+		t.Errorf("top is %+v, want only %+v", entries, result.Entry)
+	}
+	if entries, err := client.Top(1, 10); err != nil || len(entries) != 0 {
+		t.Errorf("top of another difficulty is %+v, %v", entries, err)
+	}
+
+	fetched, err := client.Replay(result.Entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched.Ticks() != replay.Ticks() || len(fetched.Runs) != len(replay.Runs) {
+		t.Errorf("fetched replay has %d ticks in %d runs, want %d in %d", fetched.Ticks(), len(fetched.Runs), replay.Ticks(), len(replay.Runs))
+	}
+	if _, err := client.Replay("0123456789abcdef"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("unknown replay: error %v, want 404", err)
+	}
+}
+
+func TestServerRejectsBadRequests(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var handler = NewServer(store)
+	var tests = []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodGet, "/scores", "", http.StatusBadRequest},
+		{http.MethodGet, "/scores?difficulty=1&limit=0", "", http.StatusBadRequest},
+		{http.MethodPost, "/scores", "{", http.StatusBadRequest},
+		{http.MethodDelete, "/scores", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/replays/entries", "", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		var rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(test.method, test.path, strings.NewReader(test.body)))
+		if rec.Code != test.status {
+			t.Errorf("%s %s: status %d, want %d", test.method, test.path, rec.Code, test.status)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package leaderboard
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/akshayxml/spaders/simulation"
+)
+
+const (
+	entriesFile = "entries.json"
+	replaysDir  = "replays"
+)
+
+var ErrNotFound = errors.New("leaderboard: not found")
+
+// Store keeps every entry in one JSON file and each replay in a file of its
+// own under dir.
+type Store struct {
+	dir     string
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, replaysDir), 0o755); err != nil {
+		return nil, err
+	}
+	var s = &Store{dir: dir}
+	data, err := os.ReadFile(filepath.Join(dir, entriesFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, err
+	}
+	if err := s.dropOutdated(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// dropOutdated removes the entries whose replays were recorded under older
+// rules. They can't be played back any more, so their scores can't be
+// checked either.
+func (s *Store) dropOutdated() error {
+	var kept = []Entry{}
+	var dropped []string
+	for _, entry := range s.entries {
+		data, err := os.ReadFile(s.replayPath(entry.ID))
+		if err != nil {
+			return err
+		}
+		var header struct {
+			Version int `json:"version"`
+		}
+		if err := json.Unmarshal(data, &header); err != nil {
+			return err
+		}
+		if header.Version == simulation.ReplayVersion {
+			kept = append(kept, entry)
+		} else {
+			dropped = append(dropped, entry.ID)
+		}
+	}
+	if len(dropped) == 0 {
+		return nil
+	}
+	if err := writeFile(filepath.Join(s.dir, entriesFile), kept); err != nil {
+		return err
+	}
+	s.entries = kept
+	for _, id := range dropped {
+		if err := os.Remove(s.replayPath(id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) replayPath(id string) string {
+	return filepath.Join(s.dir, replaysDir, id+".json")
+}
+
+// writeFile replaces path in one step so a crash never leaves half a file.
+func writeFile(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var tmp = path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func newID() (string, error) {
+	var id = make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// Add stores entry with its replay and returns it with its ID and time
+// filled in.
+func (s *Store) Add(entry Entry, replay *simulation.Replay) (Entry, error) {
+	id, err := newID()
+	if err != nil {
+		return Entry{}, err
+	}
+	entry.ID = id
+	entry.Submitted = time.Now().UTC()
+	if err := writeFile(s.replayPath(id), replay); err != nil {
+		return Entry{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entries = append(append([]Entry{}, s.entries...), entry)
+	if err := writeFile(filepath.Join(s.dir, entriesFile), entries); err != nil {
+		return Entry{}, err
+	}
+	s.entries = entries
+	return entry, nil
+}
+
+func (s *Store) ranked(difficulty int) []Entry {
+	var ranked = []Entry{}
+	for _, entry := range s.entries {
+		if entry.Difficulty == difficulty {
+			ranked = append(ranked, entry)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Ticks < ranked[j].Ticks
+	})
+	return ranked
+}
+
+// Top returns the n best entries for difficulty. Ties go to the faster game.
+func (s *Store) Top(difficulty, n int) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ranked = s.ranked(difficulty)
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// Rank returns the 1-based position of entry on its difficulty's board.
+func (s *Store) Rank(entry Entry) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, ranked := range s.ranked(entry.Difficulty) {
+		if ranked.ID == entry.ID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (s *Store) Replay(id string) (*simulation.Replay, error) {
+	if _, err := hex.DecodeString(id); err != nil || id == "" {
+		return nil, ErrNotFound
+	}
+	data, err := os.ReadFile(s.replayPath(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var replay simulation.Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return nil, err
+	}
+	return &replay, nil
+}
@@ -0,0 +1,90 @@
+package leaderboard
+
+import (
+	"testing"
+
+	"github.com/akshayxml/spaders/simulation"
+)
+
+func TestStorePersists(t *testing.T) {
+	var dir = t.TempDir()
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var replay, _ = finishedReplay(t, 3)
+	var added []Entry
+	for _, entry := range []Entry{
+		{Name: "SLOW", Score: 50, Difficulty: 3, Ticks: 900},
+		{Name: "FAST", Score: 50, Difficulty: 3, Ticks: 600},
+		{Name: "BEST", Score: 80, Difficulty: 3, Ticks: 1200},
+		{Name: "EASY", Score: 90, Difficulty: 1, Ticks: 300},
+	} {
+		entry, err := store.Add(entry, replay)
+		if err != nil {
+			t.Fatal(err)
+		}
+		added = append(added, entry)
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var top = reopened.Top(3, 10)
+	var want = []string{"BEST", "FAST", "SLOW"}
+	if len(top) != len(want) {
+		t.Fatalf("top has %d entries after reopening, want %d", len(top), len(want))
+	}
+	for i, name := range want {
+		if top[i].Name != name {
+			t.Errorf("rank %d is %s, want %s", i+1, top[i].Name, name)
+		}
+	}
+	if rank := reopened.Rank(added[1]); rank != 2 {
+		t.Errorf("FAST ranks %d after reopening, want 2", rank)
+	}
+	if !top[0].Submitted.Equal(added[2].Submitted) {
+		t.Errorf("submission time %v came back as %v", added[2].Submitted, top[0].Submitted)
+	}
+	fetched, err := reopened.Replay(added[3].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched.Ticks() != replay.Ticks() {
+		t.Errorf("replay has %d ticks after reopening, want %d", fetched.Ticks(), replay.Ticks())
+	}
+	if _, err := reopened.Replay("nothex"); err != ErrNotFound {
+		t.Errorf("Replay(nothex) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStoreDropsOutdatedReplays(t *testing.T) {
+	var dir = t.TempDir()
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var replay, _ = finishedReplay(t, 2)
+	var outdated = *replay
+	outdated.Version = simulation.ReplayVersion - 1
+	old, err := store.Add(Entry{Name: "OLD", Score: 90, Difficulty: 2}, &outdated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Add(Entry{Name: "NEW", Score: 40, Difficulty: 2}, replay); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var top = reopened.Top(2, 10)
+	if len(top) != 1 || top[0].Name != "NEW" {
+		t.Errorf("top after reopening is %v, want only NEW", top)
+	}
+	if _, err := reopened.Replay(old.ID); err != ErrNotFound {
+		t.Errorf("Replay of the outdated entry = %v, want ErrNotFound", err)
+	}
+}
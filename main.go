@@ -1,30 +1,39 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"github.com/akshayxml/spaders/models"
-	"github.com/akshayxml/spaders/models/EntityState"
+	"github.com/akshayxml/spaders/bot"
+	"github.com/akshayxml/spaders/config"
+	"github.com/akshayxml/spaders/crt"
+	"github.com/akshayxml/spaders/events"
+	"github.com/akshayxml/spaders/input"
+	"github.com/akshayxml/spaders/leaderboard"
+	"github.com/akshayxml/spaders/models/EnemyType"
+	"github.com/akshayxml/spaders/models/GameMode"
 	"github.com/akshayxml/spaders/models/Screen"
-	"github.com/akshayxml/spaders/sprites"
+	"github.com/akshayxml/spaders/netplay"
+	"github.com/akshayxml/spaders/render"
+	"github.com/akshayxml/spaders/save"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/theme"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 	"golang.org/x/image/font/sfnt"
-	"image/color"
 	_ "image/jpeg"
 	"io"
 	"log"
-	"math/rand"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 var (
+	enemyImages     map[EnemyType.EnemyType]*ebiten.Image
 	mplusFaceSource *text.GoTextFaceSource
 	bgImg           *ebiten.Image
 	enemyOneImg     *ebiten.Image
@@ -33,6 +42,7 @@ var (
 	audioContext    *audio.Context
 	player          *audio.Player
 	fontFace        *sfnt.Font
+	crtShader       []byte
 )
 
 const (
@@ -42,582 +52,576 @@ const (
 	enemyImg2Location string  = "./assets/enemyTwo.png"
 	enemyImg3Location string  = "./assets/enemyThree.png"
 	bgAudioLocation   string  = "./assets/audio.mp3"
+	crtShaderLocation string  = "./assets/crt.kage"
 	normalFontSize    float64 = 18
 	bigFontSize       float64 = 36
-	windowWidth       float64 = 640
-	windowHeight      float64 = 480
-	leftBoundary              = 50
-	rightBoundary             = windowWidth - 80
+	logicalWidth              = simulation.Width
+	logicalHeight             = simulation.Height
 	sampleRate                = 44100
 )
 
-type Game struct {
-	player        *models.Player
-	enemies       []models.Enemy
-	enemyState    models.EnemyState
-	score         int
-	bunkerSprites []models.Rectangle
-	screen        Screen.Screen
-	playStartTime int64
-	difficulty    int
-}
-
-func (g *Game) moveEnemySideways() {
-	leftMostEnemy := windowWidth
-	rightMostEnemy := 0.0
-	for i := range g.enemies {
-		if g.enemies[i].State == EntityState.Alive {
-			leftMostEnemy = min(leftMostEnemy, g.enemies[i].Position.X)
-			rightMostEnemy = max(rightMostEnemy, g.enemies[i].Position.X)
-		}
-	}
-
-	if leftMostEnemy < leftBoundary {
-		g.enemyState.HorizontalDirection = 1
-	} else if rightMostEnemy >= rightBoundary {
-		g.enemyState.HorizontalDirection = -1
-	}
-
-	for i := range g.enemies {
-		if g.enemies[i].State == EntityState.Alive {
-			g.enemies[i].Position.X += g.enemyState.HorizontalSpeed * float64(g.enemyState.HorizontalDirection)
-		}
-	}
-}
-
-func (g *Game) moveBullets() {
-	if g.player.Bullet.IsActive {
-		g.player.Bullet.Position.Y += float64(g.player.Bullet.Speed * g.player.Bullet.Direction)
-	}
+var menuItems = []string{"EASY", "MEDIUM", "DEATHZONE", "MODE", "ONLINE", "SETTINGS", "DISPLAY", "CONTINUE"}
 
-	for i := 0; i < g.enemyState.BulletCount; i++ {
-		g.enemyState.EnemyBullets[i].Position.Y += float64(g.enemyState.EnemyBullets[i].Speed * g.enemyState.EnemyBullets[i].Direction)
-	}
-}
-
-func (g *Game) addEnemyBullet(bullet models.Bullet) {
-	if g.enemyState.BulletCount < len(g.enemyState.EnemyBullets) {
-		g.enemyState.EnemyBullets[g.enemyState.BulletCount] = bullet
-	} else {
-		g.enemyState.EnemyBullets = append(g.enemyState.EnemyBullets, bullet)
-	}
-	g.enemyState.BulletCount++
+var gameModeNames = map[GameMode.GameMode]string{
+	GameMode.Single:      "1 PLAYER",
+	GameMode.Alternating: "2 PLAYERS",
+	GameMode.Coop:        "CO-OP",
+	GameMode.Versus:      "VERSUS",
 }
 
-func (g *Game) removeEnemyBullet(bulletIndex int) {
-	g.enemyState.EnemyBullets[bulletIndex], g.enemyState.EnemyBullets[g.enemyState.BulletCount-1] = g.enemyState.EnemyBullets[g.enemyState.BulletCount-1], g.enemyState.EnemyBullets[bulletIndex]
-	g.enemyState.BulletCount--
-}
+var localGameModes = []GameMode.GameMode{GameMode.Single, GameMode.Alternating, GameMode.Coop}
 
-func (g *Game) generateEnemyBullets() {
-	rand.Seed(time.Now().UnixNano())
-	if rand.Intn(100) <= g.enemyState.EnemyFireRate {
-		var enemyNumber = rand.Intn(len(g.enemies))
-		if g.enemies[enemyNumber].State == EntityState.Alive {
-			var enemyWidth = g.enemies[enemyNumber].GetEnemyWidth()
-			var enemyHeight = g.enemies[enemyNumber].GetEnemyHeight()
-			var bullet = models.Bullet{models.Position{X: g.enemies[enemyNumber].Position.X + enemyWidth/2,
-				Y: g.enemies[enemyNumber].Position.Y + enemyHeight/2},
-				1, g.difficulty, true, getSpritesHeight(sprites.GetEnemyBulletRectangles())}
-			g.addEnemyBullet(bullet)
-			bullet.Fire()
-		}
-	}
-}
-
-func (g *Game) updateDifficulty(currentTimestamp int64) {
-	var elapsedTime = currentTimestamp - g.playStartTime
-	var baseHorizontalSpeedLimit = 2.0
-	var baseHorizontalSpeedChangeIntervalMs = 10000
-	var baseVerticalMoveIntervalMs = 15000
-	var baseFireRateLimit = 10
-	var baseFireRateLimitChangeIntervalMs = 10000
-
-	for i, _ := range g.enemies {
-		var verticalMoveIntervalMs = int64(baseVerticalMoveIntervalMs - ((baseVerticalMoveIntervalMs / 3) * (g.difficulty - 1)))
-		if elapsedTime >= verticalMoveIntervalMs && elapsedTime%verticalMoveIntervalMs <= 100 {
-			g.enemies[i].Position.Y += min(2.5, float64(g.difficulty))
-		}
-	}
-
-	var horizontalSpeedChangeIntervalMs = int64(baseHorizontalSpeedChangeIntervalMs - ((baseHorizontalSpeedChangeIntervalMs / 3) * (g.difficulty - 1)))
-	var horizontalSpeedLimit = baseHorizontalSpeedLimit + float64(g.difficulty/2)
-	g.enemyState.HorizontalSpeed = min(horizontalSpeedLimit, 1+float64(elapsedTime)/float64(horizontalSpeedChangeIntervalMs*10))
+const (
+	modeMenuItem     = 3
+	onlineMenuItem   = 4
+	settingsMenuItem = 5
+	displayMenuItem  = 6
+	continueMenuItem = 7
+)
 
-	var fireRateLimitChangeIntervalMs = int64(baseFireRateLimitChangeIntervalMs - ((baseFireRateLimitChangeIntervalMs / 3) * (g.difficulty - 1)))
-	g.enemyState.EnemyFireRate = min(baseFireRateLimit*g.difficulty, int(elapsedTime/fireRateLimitChangeIntervalMs))
+type Game struct {
+	world         *simulation.World
+	screen        Screen.Screen
+	difficulty    int
+	mode          GameMode.GameMode
+	turns         []*simulation.World
+	currentTurn   int
+	stats         []turnStats
+	menuSelection int
+	paused        bool
+	config        *config.Config
+	actions       *input.ActionMap
+	coopActions   *input.ActionMap
+	settings      settingsState
+	display       displayState
+	onlineMenu    onlineMenuState
+	online        *onlineGame
+	spectate      *spectateGame
+	broadcast     *netplay.Broadcast
+	replay        *simulation.Replay
+	leaderboard   *leaderboard.Client
+	submission    scoreSubmission
+	hasSave       bool
+	autopilot     *bot.Autopilot
+	// Runs the autopilot played in, or that were slowed down or stepped
+	// through with the debug overlay, are not submitted to the leaderboard.
+	assisted   bool
+	attract    attractState
+	highScores []leaderboard.Entry
+	top        topScores
+	events     *events.Bus
+	effects    *effectsState
+	crt        *crtRenderer
+	// The frame is drawn at the logical size into canvas, then scaled onto
+	// the window where view says.
+	canvas  *ebiten.Image
+	view    input.View
+	capture captureState
+	debug   debugState
 }
 
-func (g *Game) renderScore(screen *ebiten.Image, neonGreen color.RGBA) {
-
+func (g *Game) renderCurrentPlayer(screen *ebiten.Image, palette theme.Palette) {
 	textOp := &text.DrawOptions{}
-	textOp.GeoM.Translate(50, 13)
-	textOp.ColorScale.ScaleWithColor(color.White)
-	text.Draw(screen, "SCORE", &text.GoTextFace{
-		Source: mplusFaceSource,
-		Size:   normalFontSize,
-	}, textOp)
-
-	textOp = &text.DrawOptions{}
-	textOp.GeoM.Translate(150, 13)
-	textOp.ColorScale.ScaleWithColor(neonGreen)
-	text.Draw(screen, strconv.Itoa(g.score), &text.GoTextFace{
+	textOp.GeoM.Translate(250, 13)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	text.Draw(screen, "PLAYER "+strconv.Itoa(g.currentTurn+1), &text.GoTextFace{
 		Source: mplusFaceSource,
 		Size:   normalFontSize,
 	}, textOp)
 }
 
-func (g *Game) renderLives(screen *ebiten.Image) {
-	textOp := &text.DrawOptions{}
-	textOp.GeoM.Translate(400, 13)
-	textOp.ColorScale.ScaleWithColor(color.White)
-	text.Draw(screen, "LIVES", &text.GoTextFace{
-		Source: mplusFaceSource,
-		Size:   normalFontSize,
-	}, textOp)
-
-	playerImgPositions := []struct{ x, y float64 }{
-		{480, 10},
-		{530, 10},
-		{580, 10},
-	}
-
-	for i := range playerImgPositions {
-		if i < g.player.Lives {
-			for _, rect := range sprites.GetPlayerRectangles() {
-				ebitenutil.DrawRect(screen, rect.Position.X+playerImgPositions[i].x, rect.Position.Y+playerImgPositions[i].y,
-					rect.Width, rect.Height, rect.Color)
-			}
-		}
-	}
+func (g *Game) renderWorld(screen *ebiten.Image, palette theme.Palette) {
+	render.DrawWorld(screenCanvas{screen}, g.world, palette)
+	g.effects.particles.Draw(screen)
+	g.drawPopups(screen, palette)
 }
 
-func (g *Game) renderPlayer(screen *ebiten.Image) {
-	for _, rect := range sprites.GetPlayerRectangles() {
-		ebitenutil.DrawRect(screen, rect.Position.X+g.player.Position.X, rect.Position.Y+g.player.Position.Y,
-			rect.Width, rect.Height, rect.Color)
+func (g *Game) DrawMenu(screen *ebiten.Image, palette theme.Palette) {
+	msg := "SPADERS"
+	face := &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   bigFontSize,
 	}
-}
+	textOp := &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), float64(logicalHeight/2))
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	textOp.PrimaryAlign = text.AlignCenter
+	textOp.SecondaryAlign = text.AlignCenter
+	text.Draw(screen, msg, face, textOp)
 
-func (g *Game) renderBunker(screen *ebiten.Image) {
-	for _, bunkerSprite := range g.bunkerSprites {
-		ebitenutil.DrawRect(screen, bunkerSprite.Position.X, bunkerSprite.Position.Y,
-			bunkerSprite.Width, bunkerSprite.Height, bunkerSprite.Color)
+	msg = "PRESS " + g.keyName(input.Confirm) + " TO START"
+	face = &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   normalFontSize,
 	}
-}
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), float64(logicalHeight/2+40))
+	textOp.ColorScale.ScaleWithColor(palette.Text)
+	textOp.PrimaryAlign = text.AlignCenter
+	text.Draw(screen, msg, face, textOp)
 
-func (g *Game) renderBullets(screen *ebiten.Image) {
-	if g.player.Bullet.IsActive {
-		for _, rect := range sprites.GetPlayerBulletRectangles() {
-			ebitenutil.DrawRect(screen, rect.Position.X+g.player.Bullet.Position.X, rect.Position.Y+g.player.Bullet.Position.Y,
-				rect.Width, rect.Height, rect.Color)
-		}
-	}
-	for i := 0; i < g.enemyState.BulletCount; i++ {
-		for _, rect := range sprites.GetEnemyBulletRectangles() {
-			ebitenutil.DrawRect(screen, rect.Position.X+g.enemyState.EnemyBullets[i].Position.X, rect.Position.Y+g.enemyState.EnemyBullets[i].Position.Y,
-				rect.Width, rect.Height, rect.Color)
+	for i := 0; i < g.menuItemCount(); i++ {
+		msg = g.menuItemLabel(i)
+		if g.menuSelection == i {
+			msg = "->" + msg
 		}
+		textOp = &text.DrawOptions{}
+		textOp.GeoM.Translate(float64(logicalWidth/2), menuItemY(i))
+		textOp.ColorScale.ScaleWithColor(palette.Accent)
+		textOp.PrimaryAlign = text.AlignCenter
+		text.Draw(screen, msg, face, textOp)
 	}
 }
 
-func getSpritesWidth(sprites []models.Rectangle) float64 {
-	var width = 0.0
-	for _, sprite := range sprites {
-		width += sprite.Width
+// menuItemCount leaves out CONTINUE when there is no saved run.
+func (g *Game) menuItemCount() int {
+	if g.hasSave {
+		return len(menuItems)
 	}
-	return width
-}
-
-func getSpritesHeight(sprites []models.Rectangle) float64 {
-	var height = 0.0
-	for _, sprite := range sprites {
-		height += sprite.Width
-	}
-	return height
-}
-
-func getEnemies(rows int, yPosStart float64, img *ebiten.Image, scale float64) (float64, []models.Enemy) {
-	var cols = 10
-	var imgWidth = float64(img.Bounds().Dx()) * scale
-	var imgHeight = float64(img.Bounds().Dy()) * scale
-	var xPosStart = float64(windowWidth/2) - (imgWidth / 2) - 40 - imgWidth*5
-	var xGap = imgWidth + 10
-	var yGap = imgHeight + 10
-	var enemies = []models.Enemy{}
-
-	for y, rowCnt := float64(yPosStart), 0; y < float64(windowHeight) && rowCnt < rows; y, rowCnt = y+yGap, rowCnt+1 {
-		for x, colCnt := float64(xPosStart), 0; x < float64(windowWidth) && colCnt < cols; x, colCnt = x+xGap, colCnt+1 {
-			var enemy = models.Enemy{models.Position{x, y}, img, scale, EntityState.Alive}
-			enemies = append(enemies, enemy)
-		}
-	}
-
-	return yGap * float64(rows), enemies
+	return continueMenuItem
 }
 
-func (g *Game) renderEnemies(screen *ebiten.Image) {
-	for _, enemy := range g.enemies {
-		if enemy.State == EntityState.Alive {
-			opts := &ebiten.DrawImageOptions{}
-			opts.GeoM.Scale(enemy.Scale, enemy.Scale)
-			opts.GeoM.Translate(enemy.Position.X, enemy.Position.Y)
-			screen.DrawImage(enemy.Img, opts)
-		}
+func (g *Game) menuItemLabel(i int) string {
+	if i == modeMenuItem {
+		return gameModeNames[g.mode]
 	}
+	return menuItems[i]
 }
 
-func setupEnemies() []models.Enemy {
-	var allEnemies = []models.Enemy{}
-	var enemyYPos float64 = 60
-	var yGap, enemies = getEnemies(1, enemyYPos, enemyThreeImg, 0.5)
-	allEnemies = append(allEnemies, enemies[:]...)
-
-	enemyYPos += yGap
-	yGap, enemies = getEnemies(2, enemyYPos, enemyTwoImg, 0.6)
-	allEnemies = append(allEnemies, enemies[:]...)
-
-	enemyYPos += yGap
-	yGap, enemies = getEnemies(2, enemyYPos, enemyOneImg, 0.6)
-	allEnemies = append(allEnemies, enemies[:]...)
-
-	return allEnemies
-}
-
-func setupBunkers() []models.Rectangle {
-	var imgWidth float64 = 0
-	for _, rect := range sprites.GetBunkerRectangles() {
-		imgWidth += rect.Width
-	}
-	bunkerPositions := []struct{ x, y float64 }{
-		{float64(windowWidth/5 - float64(imgWidth/2)), float64(windowHeight - 100)},
-		{float64(2*(windowWidth/5) - float64(imgWidth/2)), float64(windowHeight - 100)},
-		{float64(3*(windowWidth/5) - float64(imgWidth/2)), float64(windowHeight - 100)},
-		{float64(4*(windowWidth/5) - float64(imgWidth/2)), float64(windowHeight - 100)},
-	}
-	var bunkerSprites = []models.Rectangle{}
-	for i := range bunkerPositions {
-		for _, bunkerSprite := range sprites.GetBunkerRectangles() {
-			bunkerSprite.Position.X += bunkerPositions[i].x
-			bunkerSprite.Position.Y += bunkerPositions[i].y
-			bunkerSprites = append(bunkerSprites, bunkerSprite)
-		}
+func (g *Game) DrawPaused(screen *ebiten.Image, palette theme.Palette) {
+	face := &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   bigFontSize,
 	}
-
-	return bunkerSprites
+	textOp := &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), float64(logicalHeight/2))
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	textOp.PrimaryAlign = text.AlignCenter
+	textOp.SecondaryAlign = text.AlignCenter
+	text.Draw(screen, "PAUSED", face, textOp)
 }
 
-func (g *Game) detectCollision() {
-	if g.player.Bullet.IsActive {
-		for i := range g.bunkerSprites {
-			if g.bunkerSprites[i].Height > 0 {
-				var bunkerSpriteLeft = g.bunkerSprites[i].Position.X
-				var bunkerSpriteRight = g.bunkerSprites[i].Position.X + g.bunkerSprites[i].Width
-				var bunkerSpriteTop = g.bunkerSprites[i].Position.Y
-				var bunkerSpriteBottom = g.bunkerSprites[i].Position.Y + g.bunkerSprites[i].Height
-				if g.player.Bullet.HasCollided(bunkerSpriteLeft, bunkerSpriteRight, bunkerSpriteTop, bunkerSpriteBottom) {
-					g.bunkerSprites[i].Height -= sprites.GetBunkerRectangles()[0].Height
-					g.player.Bullet.IsActive = false
-				}
-			}
-		}
-
-		for i, enemy := range g.enemies {
-			if enemy.State == EntityState.Alive {
-				var enemyLeftEdge = enemy.Position.X
-				var enemyRightEdge = enemy.Position.X + enemy.GetEnemyWidth()
-				var enemyTopEdge = enemy.Position.Y
-				var enemyBottomEdge = enemy.Position.Y + enemy.GetEnemyHeight()
-				if g.player.Bullet.HasCollided(enemyLeftEdge, enemyRightEdge, enemyTopEdge, enemyBottomEdge) {
-					g.player.Bullet.IsActive = false
-					g.enemies[i].State = EntityState.Dead
-					g.score += 5
-					g.enemyState.EnemyCount--
-					if g.enemyState.EnemyCount == 0 {
-						g.screen = Screen.GameOver
-					}
-				}
-			}
-		}
-
-		if g.player.Bullet.Position.Y <= 5 {
-			g.player.Bullet.IsActive = false
-		}
-	}
-
-	for i := 0; i < g.enemyState.BulletCount; i++ {
-		if g.enemyState.EnemyBullets[i].IsActive {
-			for j := range g.bunkerSprites {
-				if g.bunkerSprites[j].Height > 0 {
-					var bunkerSpriteLeft = g.bunkerSprites[j].Position.X
-					var bunkerSpriteRight = g.bunkerSprites[j].Position.X + g.bunkerSprites[j].Width
-					var bunkerSpriteTop = g.bunkerSprites[j].Position.Y
-					var bunkerSpriteBottom = g.bunkerSprites[j].Position.Y + g.bunkerSprites[j].Height
-					if g.enemyState.EnemyBullets[i].HasCollided(bunkerSpriteLeft, bunkerSpriteRight, bunkerSpriteTop, bunkerSpriteBottom) {
-						g.bunkerSprites[j].Height -= sprites.GetBunkerRectangles()[0].Height
-						g.bunkerSprites[j].Position.Y += sprites.GetBunkerRectangles()[0].Height
-						g.enemyState.EnemyBullets[i].IsActive = false
-					}
-				}
-			}
-
-			for _, playerSprite := range sprites.GetPlayerRectangles() {
-				var playerLeftEdge = g.player.Position.X + playerSprite.Position.X
-				var playerRightEdge = g.player.Position.X + playerSprite.Position.X + playerSprite.Width
-				var playerTopEdge = g.player.Position.Y + playerSprite.Position.Y
-				var playerBottomEdge = g.player.Position.Y + playerSprite.Position.Y + playerSprite.Height
-				if g.enemyState.EnemyBullets[i].HasCollided(playerLeftEdge, playerRightEdge, playerTopEdge, playerBottomEdge) {
-					g.player.Lives--
-					g.enemyState.EnemyBullets[i].IsActive = false
-					if g.player.Lives == 0 {
-						g.screen = Screen.GameOver
-					}
-				}
-			}
-
-			if g.player.Bullet.IsActive {
-				if g.player.Bullet.HasCollidedBullets(g.enemyState.EnemyBullets[i]) {
-					g.player.Bullet.IsActive = false
-					g.enemyState.EnemyBullets[i].IsActive = false
-					g.score += 3
-				}
-			}
-
-			if g.enemyState.EnemyBullets[i].Position.Y >= windowHeight-20 {
-				g.enemyState.EnemyBullets[i].IsActive = false
-			}
-		}
-	}
-
-	for _, enemy := range g.enemies {
-		if enemy.State == EntityState.Alive && enemy.Position.Y >= g.player.Position.Y {
-			g.player.Lives = 0
-			g.screen = Screen.GameOver
-		}
-	}
-
-	var i = 0
-	for i < g.enemyState.BulletCount {
-		if !g.enemyState.EnemyBullets[i].IsActive {
-			g.removeEnemyBullet(i)
-		} else {
-			i++
-		}
+func (g *Game) DrawGameOver(screen *ebiten.Image, palette theme.Palette) {
+	msg := "GAME OVER"
+	if g.world.EnemyState.EnemyCount == 0 {
+		msg = "YOU'VE WON!!!"
 	}
-}
-
-func (g *Game) DrawMenu(screen *ebiten.Image, neonGreen color.RGBA) {
-	msg := "SPADERS"
 	face := &text.GoTextFace{
 		Source: mplusFaceSource,
 		Size:   bigFontSize,
 	}
 	textOp := &text.DrawOptions{}
-	textOp.GeoM.Translate(float64(windowWidth/2), float64(windowHeight/2))
-	textOp.ColorScale.ScaleWithColor(neonGreen)
+	textOp.GeoM.Translate(float64(logicalWidth/2), float64(logicalHeight/2))
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
 	textOp.PrimaryAlign = text.AlignCenter
 	textOp.SecondaryAlign = text.AlignCenter
 	text.Draw(screen, msg, face, textOp)
 
-	msg = "PRESS SPACE TO START"
 	face = &text.GoTextFace{
 		Source: mplusFaceSource,
 		Size:   normalFontSize,
 	}
-	textOp = &text.DrawOptions{}
-	textOp.GeoM.Translate(float64(windowWidth/2), float64(windowHeight/2+40))
-	textOp.ColorScale.ScaleWithColor(color.White)
-	textOp.PrimaryAlign = text.AlignCenter
-	text.Draw(screen, msg, face, textOp)
-
-	msg = "EASY"
-	if g.difficulty == 1 {
-		msg = "->EASY"
+	var scoreLines = []string{"YOUR SCORE IS " + strconv.Itoa(g.world.Score)}
+	if g.online != nil && g.mode == GameMode.Versus {
+		scoreLines = []string{
+			"YOU SCORED " + strconv.Itoa(g.world.Score),
+			"RIVAL SCORED " + strconv.Itoa(g.rivalScore()),
+		}
+	} else if g.mode == GameMode.Alternating {
+		scoreLines = scoreLines[:0]
+		for i, turn := range g.turns {
+			scoreLines = append(scoreLines, "PLAYER "+strconv.Itoa(i+1)+" SCORED "+strconv.Itoa(turn.Score))
+		}
 	}
-	face = &text.GoTextFace{
-		Source: mplusFaceSource,
-		Size:   normalFontSize,
+	for i, stats := range g.stats {
+		if g.mode == GameMode.Alternating {
+			scoreLines = append(scoreLines, "PLAYER "+strconv.Itoa(i+1)+" "+stats.String())
+		} else {
+			scoreLines = append(scoreLines, "YOU "+stats.String())
+		}
 	}
-	textOp = &text.DrawOptions{}
-	textOp.GeoM.Translate(float64(windowWidth/2), float64(windowHeight/2)+70)
-	textOp.ColorScale.ScaleWithColor(neonGreen)
-	textOp.PrimaryAlign = text.AlignCenter
-	text.Draw(screen, msg, face, textOp)
-
-	msg = "MEDIUM"
-	if g.difficulty == 2 {
-		msg = "->MEDIUM"
+	for i, line := range scoreLines {
+		textOp = &text.DrawOptions{}
+		textOp.GeoM.Translate(float64(logicalWidth/2), float64(logicalHeight/2+40)+float64(20*i))
+		textOp.ColorScale.ScaleWithColor(palette.Text)
+		textOp.PrimaryAlign = text.AlignCenter
+		text.Draw(screen, line, face, textOp)
 	}
+
+	msg = "PRESS " + g.keyName(input.Confirm) + " TO REPLAY"
 	face = &text.GoTextFace{
 		Source: mplusFaceSource,
 		Size:   normalFontSize,
 	}
 	textOp = &text.DrawOptions{}
-	textOp.GeoM.Translate(float64(windowWidth/2), float64(windowHeight/2)+90)
-	textOp.ColorScale.ScaleWithColor(neonGreen)
+	textOp.GeoM.Translate(float64(logicalWidth/2), float64(logicalHeight/2+60)+float64(20*len(scoreLines)))
+	textOp.ColorScale.ScaleWithColor(palette.Text)
 	textOp.PrimaryAlign = text.AlignCenter
 	text.Draw(screen, msg, face, textOp)
 
-	msg = "DEATHZONE"
-	if g.difficulty == 3 {
-		msg = "->DEATHZONE"
-	}
-	face = &text.GoTextFace{
-		Source: mplusFaceSource,
-		Size:   normalFontSize,
-	}
 	textOp = &text.DrawOptions{}
-	textOp.GeoM.Translate(float64(windowWidth/2), float64(windowHeight/2)+110)
-	textOp.ColorScale.ScaleWithColor(neonGreen)
+	textOp.GeoM.Translate(float64(logicalWidth/2), float64(logicalHeight/2+100)+float64(20*len(scoreLines)))
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
 	textOp.PrimaryAlign = text.AlignCenter
-	text.Draw(screen, msg, face, textOp)
-
+	text.Draw(screen, g.submission.status, face, textOp)
 }
 
-func (g *Game) DrawGameOver(screen *ebiten.Image, neonGreen color.RGBA) {
-	msg := "GAME OVER"
-	if g.enemyState.EnemyCount == 0 {
-		msg = "YOU'VE WON!!!"
-	}
+func (g *Game) DrawTurn(screen *ebiten.Image, palette theme.Palette) {
+	msg := "PLAYER " + strconv.Itoa(g.currentTurn+1)
 	face := &text.GoTextFace{
 		Source: mplusFaceSource,
 		Size:   bigFontSize,
 	}
 	textOp := &text.DrawOptions{}
-	textOp.GeoM.Translate(float64(windowWidth/2), float64(windowHeight/2))
-	textOp.ColorScale.ScaleWithColor(neonGreen)
+	textOp.GeoM.Translate(float64(logicalWidth/2), float64(logicalHeight/2))
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
 	textOp.PrimaryAlign = text.AlignCenter
 	textOp.SecondaryAlign = text.AlignCenter
 	text.Draw(screen, msg, face, textOp)
 
-	msg = "YOUR SCORE IS " + strconv.Itoa(g.score)
+	msg = "PRESS " + g.keyName(input.Confirm) + " TO PLAY"
 	face = &text.GoTextFace{
 		Source: mplusFaceSource,
 		Size:   normalFontSize,
 	}
 	textOp = &text.DrawOptions{}
-	textOp.GeoM.Translate(float64(windowWidth/2), float64(windowHeight/2+40))
-	textOp.ColorScale.ScaleWithColor(color.White)
+	textOp.GeoM.Translate(float64(logicalWidth/2), float64(logicalHeight/2+40))
+	textOp.ColorScale.ScaleWithColor(palette.Text)
 	textOp.PrimaryAlign = text.AlignCenter
 	text.Draw(screen, msg, face, textOp)
+}
 
-	msg = "PRESS SPACE TO REPLAY"
-	face = &text.GoTextFace{
+func (g *Game) keyName(action input.Action) string {
+	var keys = g.actions.Keys(action)
+	if len(keys) == 0 {
+		return "?"
+	}
+	return strings.ToUpper(keys[0].String())
+}
+
+func (g *Game) togglePause() {
+	g.paused = !g.paused
+}
+
+func (g *Game) toggleAutopilot() {
+	if g.autopilot != nil {
+		g.autopilot = nil
+		return
+	}
+	g.autopilot = &bot.Autopilot{}
+	g.assisted = true
+}
+
+func (g *Game) DrawAutopilot(screen *ebiten.Image, palette theme.Palette) {
+	textOp := &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), 40)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	textOp.PrimaryAlign = text.AlignCenter
+	text.Draw(screen, "AUTOPILOT", &text.GoTextFace{
 		Source: mplusFaceSource,
 		Size:   normalFontSize,
+	}, textOp)
+}
+
+// endTurn is called whenever a player loses a life or the formation is
+// cleared. In alternating mode play passes to the other player if they can
+// still play; the game is over once nobody can.
+func (g *Game) endTurn() {
+	if g.mode != GameMode.Alternating {
+		if !g.world.CanPlay() {
+			g.screen = Screen.GameOver
+			g.recordHighScore()
+			g.submitScore()
+		}
+		return
+	}
+
+	var next = (g.currentTurn + 1) % len(g.turns)
+	if g.turns[next].CanPlay() {
+		g.currentTurn = next
+		g.world = g.turns[next]
+		g.keepEffects()
+		g.screen = Screen.Turn
+	} else if !g.world.CanPlay() {
+		g.screen = Screen.GameOver
 	}
-	textOp = &text.DrawOptions{}
-	textOp.GeoM.Translate(float64(windowWidth/2), float64(windowHeight/2+80))
-	textOp.ColorScale.ScaleWithColor(color.White)
-	textOp.PrimaryAlign = text.AlignCenter
-	text.Draw(screen, msg, face, textOp)
 }
 
 func (g *Game) reset() {
 	g.screen = Screen.Menu
-	g.playStartTime = time.Now().UnixMilli()
-	g.score = 0
-	g.bunkerSprites = setupBunkers()
-	g.enemies = setupEnemies()
-	g.player = &models.Player{
-		Position: models.Position{
-			X: (windowWidth / 2),
-			Y: windowHeight - 40,
-		},
-		Lives: 3,
-		Speed: 2.0,
-		Bullet: models.Bullet{
-			Direction: -1,
-			Speed:     3,
-			IsActive:  false,
-		},
-	}
-	g.enemyState = models.EnemyState{
-		HorizontalDirection: 1,
-		HorizontalSpeed:     1.0,
-		BulletCount:         0,
-		EnemyCount:          len(g.enemies),
-		EnemyFireRate:       1,
-	}
-	if g.difficulty == 3 {
-		g.player.Lives = 1
+	g.paused = false
+	g.autopilot = nil
+	g.assisted = false
+	g.stopAttract()
+	g.closeOnline()
+	g.closeSpectate()
+	var turnCount, playerCount = 1, 1
+	if g.mode == GameMode.Alternating {
+		turnCount = 2
+	} else if g.mode == GameMode.Coop {
+		playerCount = 2
+	}
+	var setup = simulation.Setup{Mode: g.mode, Difficulty: g.difficulty, Players: playerCount}
+	var seed = time.Now().UnixNano()
+	for i := 0; i < turnCount; i++ {
+		setup.Seeds = append(setup.Seeds, seed+int64(i))
+	}
+	g.turns = setup.NewWorlds()
+	g.currentTurn = 0
+	g.stats = make([]turnStats, len(g.turns))
+	g.world = g.turns[0]
+	g.replay = simulation.NewReplay(setup)
+	g.submission = scoreSubmission{}
+	g.hasSave = save.Exists()
+	if g.menuSelection >= g.menuItemCount() {
+		g.menuSelection = 0
+	}
+	g.startBroadcast(setup, g.turns, 0)
+}
+
+func (g *Game) playerActions(i int) *input.ActionMap {
+	if i == 0 {
+		return g.actions
+	}
+	return g.coopActions
+}
+
+// assignCoopGamepads gives the second player a gamepad of their own. With a
+// single gamepad connected the first player stays on the keyboard.
+func (g *Game) assignCoopGamepads() {
+	if len(g.actions.Gamepads()) >= 2 {
+		g.actions.SetGamepadIndex(0)
+		g.coopActions.SetGamepadIndex(1)
+	} else {
+		g.actions.SetGamepadIndex(input.NoGamepad)
+		g.coopActions.SetGamepadIndex(0)
+	}
+}
+
+func actionInput(actions *input.ActionMap) simulation.Input {
+	var in simulation.Input
+	if actions.IsPressed(input.MoveLeft) {
+		in |= simulation.InputLeft
+	}
+	if actions.IsPressed(input.MoveRight) {
+		in |= simulation.InputRight
+	}
+	if actions.IsJustPressed(input.Fire) {
+		in |= simulation.InputFire
 	}
+	return in
+}
+
+func (g *Game) playerInput(i int) simulation.Input {
+	if i == 0 && g.autopilot != nil {
+		return g.autopilot.Input(g.world)
+	}
+	var in = actionInput(g.playerActions(i))
+	if i == 0 {
+		in |= g.pointerInput(g.world.Players[0])
+	}
+	return in
+}
+
+func (g *Game) selectMenuItem() {
+	if g.menuSelection == settingsMenuItem {
+		g.screen = Screen.Settings
+		return
+	}
+	if g.menuSelection == displayMenuItem {
+		g.screen = Screen.Display
+		return
+	}
+	if g.menuSelection == modeMenuItem {
+		for i, mode := range localGameModes {
+			if mode == g.mode {
+				g.mode = localGameModes[(i+1)%len(localGameModes)]
+				break
+			}
+		}
+		return
+	}
+	if g.menuSelection == onlineMenuItem {
+		g.screen = Screen.Online
+		return
+	}
+	if g.menuSelection == continueMenuItem {
+		g.continueRun()
+		return
+	}
+	g.difficulty = g.menuSelection + 1
+	g.reset()
+	g.screen = Screen.Play
 }
 
 func (g *Game) Update() error {
+	if ebiten.IsWindowBeingClosed() {
+		g.saveRun()
+		return ebiten.Termination
+	}
+	defer g.updateEffects()
+	g.actions.Update()
+	g.coopActions.Update()
+	if g.actions.IsJustPressed(input.Fullscreen) && !g.settings.capturing {
+		g.toggleFullscreen()
+	}
+	g.updateCapture()
+	g.updateDebug()
+	if g.screen != Screen.Play {
+		g.actions.SetGamepadIndex(input.AllGamepads)
+	}
 	if g.screen == Screen.Menu {
-		if inpututil.IsKeyJustPressed(ebiten.KeySpace) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
-			g.reset()
-			g.screen = Screen.Play
-			fmt.Print(g.difficulty)
+		if g.updateAttract() {
+			return nil
 		}
-		if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
-			g.difficulty = g.difficulty%3 + 1
+		if item, ok := g.tappedMenuItem(); ok {
+			g.menuSelection = item
+			g.selectMenuItem()
+			return nil
 		}
-		if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
-			g.difficulty--
-			if g.difficulty == 0 {
-				g.difficulty = 3
-			}
+		if g.actions.IsJustPressed(input.Confirm) {
+			g.selectMenuItem()
+			return nil
+		}
+		if g.actions.IsJustPressed(input.MenuDown) {
+			g.menuSelection = (g.menuSelection + 1) % g.menuItemCount()
+		}
+		if g.actions.IsJustPressed(input.MenuUp) {
+			g.menuSelection = (g.menuSelection + g.menuItemCount() - 1) % g.menuItemCount()
+		}
+		if g.menuSelection < modeMenuItem {
+			g.difficulty = g.menuSelection + 1
+		}
+	} else if g.screen == Screen.Settings {
+		g.updateSettings()
+	} else if g.screen == Screen.Display {
+		g.updateDisplay()
+	} else if g.screen == Screen.Online {
+		g.updateOnlineMenu()
+	} else if g.screen == Screen.Spectate {
+		g.updateSpectate()
+	} else if g.screen == Screen.Play && g.online != nil {
+		g.updateOnline()
+	} else if g.screen == Screen.Turn {
+		if g.actions.IsJustPressed(input.Confirm) || g.isTapped() {
+			g.screen = Screen.Play
 		}
 	} else if g.screen == Screen.GameOver {
-		if inpututil.IsKeyJustPressed(ebiten.KeySpace) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		g.pollSubmission()
+		if g.actions.IsJustPressed(input.Confirm) || g.isTapped() {
 			g.reset()
 		}
 	} else if g.screen == Screen.Play {
-		if ebiten.IsKeyPressed(ebiten.KeyEscape) {
-			g.screen = Screen.Menu
+		if g.actions.IsJustPressed(input.Back) {
+			g.saveRun()
+			g.reset()
+			return nil
+		}
+		if g.actions.IsJustPressed(input.Pause) {
+			g.togglePause()
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
-			g.player.MoveLeft()
+		if g.actions.IsJustPressed(input.Autopilot) {
+			g.toggleAutopilot()
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
-			g.player.MoveRight(rightBoundary)
+		if g.paused && !g.debugStep() {
+			if g.isTapped() {
+				g.togglePause()
+			}
+			return nil
 		}
-		currentTimestamp := time.Now().UnixMilli()
-		if inpututil.IsKeyJustPressed(ebiten.KeySpace) && !g.player.Bullet.IsActive {
-			g.player.Bullet.Position = models.Position{X: g.player.Position.X + 20, Y: g.player.Position.Y}
-			g.player.Bullet.Height = getSpritesHeight(sprites.GetPlayerBulletRectangles())
-			g.player.Bullet.Fire()
+		if !g.paused && g.debugHoldsTick() {
+			return nil
+		}
+		if g.mode == GameMode.Coop {
+			g.assignCoopGamepads()
+		}
+		var inputs = make([]simulation.Input, len(g.world.Players))
+		for i := range inputs {
+			inputs[i] = g.playerInput(i)
+		}
+		g.world.Step(inputs)
+		g.events.PublishAll(g.world.Events)
+		var frame = g.localFrame(inputs)
+		g.replay.Record(frame)
+		g.broadcastFrame(frame)
+		if g.world.LifeLost || !g.world.CanPlay() {
+			g.endTurn()
 		}
-
-		g.moveEnemySideways()
-		g.generateEnemyBullets()
-		g.moveBullets()
-		g.updateDifficulty(currentTimestamp)
 	}
 	return nil
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
-	var neonGreen = color.RGBA{0x39, 0xFF, 0x14, 0xFF}
+	var palette = g.palette()
+	if g.canvas == nil {
+		g.canvas = ebiten.NewImage(int(logicalWidth), int(logicalHeight))
+	}
+	g.canvas.Clear()
+	g.drawScreen(g.canvas, palette)
+	drawGels(g.canvas, palette)
+	var frame = g.canvas
+	if g.config.CRT {
+		frame = g.crt.Apply(frame)
+	}
+	g.takeScreenshot(frame)
+	g.present(screen, frame)
+}
 
-	imgOp := &ebiten.DrawImageOptions{}
-	imgOp.GeoM.Scale(0.5, 0.5)
-	screen.DrawImage(bgImg, imgOp)
+func (g *Game) drawScreen(screen *ebiten.Image, palette theme.Palette) {
+	if palette.Background {
+		imgOp := &ebiten.DrawImageOptions{}
+		imgOp.GeoM.Scale(0.5, 0.5)
+		screen.DrawImage(bgImg, imgOp)
+	}
 
-	g.renderScore(screen, neonGreen)
-	g.renderLives(screen)
+	render.DrawHUD(screenCanvas{screen}, g.world, palette)
+	if g.mode == GameMode.Alternating && g.screen != Screen.Menu {
+		g.renderCurrentPlayer(screen, palette)
+	}
 
 	if g.screen == Screen.Menu {
-		g.DrawMenu(screen, neonGreen)
+		g.DrawTitle(screen, palette)
+	} else if g.screen == Screen.Settings {
+		g.DrawSettings(screen, palette)
+	} else if g.screen == Screen.Display {
+		g.DrawDisplay(screen, palette)
 	} else if g.screen == Screen.GameOver {
-		g.DrawGameOver(screen, neonGreen)
+		g.DrawGameOver(screen, palette)
 		return
+	} else if g.screen == Screen.Turn {
+		g.DrawTurn(screen, palette)
+	} else if g.screen == Screen.Online {
+		g.DrawOnlineMenu(screen, palette)
 	} else {
-		g.detectCollision()
-		g.renderBullets(screen)
-		g.renderBunker(screen)
-		g.renderEnemies(screen)
-		g.renderPlayer(screen)
+		g.renderWorld(screen, palette)
+		if g.debug.on {
+			g.DrawDebug(screen)
+		}
 
-		vector.StrokeLine(screen, leftBoundary, float32(windowHeight-10),
-			float32(windowWidth-50), float32(windowHeight-10), 2, neonGreen, true)
+		if g.autopilot != nil {
+			g.DrawAutopilot(screen, palette)
+		}
+		if g.paused {
+			g.DrawPaused(screen, palette)
+		}
+		if g.online != nil {
+			g.DrawOnlineStatus(screen, palette)
+		}
+		if g.spectate != nil {
+			g.DrawSpectateStatus(screen, palette)
+		}
 	}
 }
 
+// Layout makes the screen as big as the window in real pixels, so that the
+// logical screen can be scaled up sharply on HiDPI displays.
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	return int(windowWidth), int(windowHeight)
+	var scale = ebiten.Monitor().DeviceScaleFactor()
+	screenWidth, screenHeight = int(float64(outsideWidth)*scale), int(float64(outsideHeight)*scale)
+	g.view = fitView(screenWidth, screenHeight, g.config.SmoothScaling)
+	g.actions.Pointers().SetView(g.view)
+	g.coopActions.Pointers().SetView(g.view)
+	return screenWidth, screenHeight
 }
 
 func init() {
@@ -642,6 +646,17 @@ func init() {
 		log.Fatal(err)
 	}
 
+	crtShader, err = os.ReadFile(crtShaderLocation)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	enemyImages = map[EnemyType.EnemyType]*ebiten.Image{
+		EnemyType.One:   enemyOneImg,
+		EnemyType.Two:   enemyTwoImg,
+		EnemyType.Three: enemyThreeImg,
+	}
+
 	textFile, err := os.Open(fontLocation)
 	s, err := text.NewGoTextFaceSource(textFile)
 	if err != nil {
@@ -674,9 +689,25 @@ func playAudio(f io.Reader) error {
 }
 
 func main() {
+	hostAddress := flag.String("host", "", "host an online game on this address, e.g. :7777")
+	joinAddress := flag.String("join", "", "join the online game hosted at this address")
+	inputDelay := flag.Int("delay", netplay.DefaultInputDelay, "online input delay in ticks")
+	versus := flag.Bool("versus", false, "host an online versus match instead of co-op")
+	rollback := flag.Bool("rollback", false, "host an online match with rollback instead of lockstep")
+	latency := flag.Duration("latency", 0, "simulated network latency for online play")
+	jitter := flag.Duration("jitter", 0, "simulated network jitter for online play")
+	loss := flag.Float64("loss", 0, "simulated fraction of lost network messages for online play")
+	broadcastAddress := flag.String("broadcast", "", "let spectators watch your games on this address, e.g. :7778")
+	spectateAddress := flag.String("spectate", "", "watch the game broadcast at this address")
+	leaderboardURL := flag.String("leaderboard", "", "submit single player scores to the leaderboard server at this URL")
+	crtCPU := flag.Bool("crt-cpu", false, "run the CRT effect on the CPU instead of in a shader")
+	flag.Parse()
+
 	fmt.Println("SPADERS")
-	ebiten.SetWindowSize(int(windowWidth), int(windowHeight))
+	ebiten.SetWindowSize(int(logicalWidth), int(logicalHeight))
 	ebiten.SetWindowTitle("Spaders")
+	ebiten.SetWindowClosingHandled(true)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 
 	f, err := os.Open(bgAudioLocation)
 	if err != nil {
@@ -688,9 +719,67 @@ func main() {
 		log.Fatal(err)
 	}
 
+	cfg, err := config.Load()
+	if err != nil {
+		log.Printf("failed to load config, using defaults: %v", err)
+	}
+
 	g := &Game{}
 	g.difficulty = 1
+	g.events = events.NewBus()
+	g.effects = newEffects(g.events, g.palette)
+	subscribeSounds(g.events, func() bool { return g.screen == Screen.Menu })
+	g.subscribeStats()
+	g.onlineMenu.address = defaultOnlineAddress
+	g.onlineMenu.inputDelay = *inputDelay
+	g.onlineMenu.mode = GameMode.Coop
+	if *versus {
+		g.onlineMenu.mode = GameMode.Versus
+	}
+	g.onlineMenu.rollback = *rollback
+	g.onlineMenu.conditions = netplay.Conditions{Latency: *latency, Jitter: *jitter, Loss: *loss}
+	g.config = cfg
+	ebiten.SetFullscreen(cfg.Fullscreen)
+	var shader = crtShader
+	if *crtCPU {
+		shader = nil
+	}
+	g.crt, err = newCRTRenderer(shader, crt.Default)
+	if err != nil {
+		log.Printf("failed to compile the CRT shader, running it on the CPU: %v", err)
+	}
+	g.actions = input.NewActionMap(cfg.Keyboard, input.DefaultBindings())
+	g.actions.SetDeadZone(cfg.GamepadDeadZone)
+	g.coopActions = input.NewActionMap(cfg.Player2Keyboard, input.DefaultPlayer2Bindings())
+	g.coopActions.SetDeadZone(cfg.GamepadDeadZone)
+	g.actions.SetPartner(g.coopActions)
+	if *leaderboardURL != "" {
+		cfg.LeaderboardURL = *leaderboardURL
+	}
+	if cfg.LeaderboardURL != "" {
+		g.leaderboard = leaderboard.NewClient(cfg.LeaderboardURL)
+	}
+	if *broadcastAddress != "" {
+		g.broadcast, err = netplay.NewBroadcast(*broadcastAddress)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer g.broadcast.Close()
+	}
 	g.reset()
+	if *hostAddress != "" {
+		g.onlineMenu.address = *hostAddress
+		g.screen = Screen.Online
+		g.hostOnline()
+	} else if *joinAddress != "" {
+		g.onlineMenu.address = *joinAddress
+		g.screen = Screen.Online
+		g.joinOnline()
+	} else if *spectateAddress != "" {
+		if err := g.startSpectating(*spectateAddress); err != nil {
+			log.Fatal(err)
+		}
+	}
 	if err := ebiten.RunGame(g); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,10 @@
+package AttractPage
+
+type AttractPage int
+
+const (
+	Logo       AttractPage = iota
+	ScoreTable AttractPage = iota
+	HighScores AttractPage = iota
+	Demo       AttractPage = iota
+)
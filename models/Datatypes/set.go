@@ -38,3 +38,11 @@ func (s Set[T]) Get() []T {
 	}
 	return elements
 }
+
+// CopyFrom makes s hold exactly what src holds, reusing s's memory.
+func (s *Set[T]) CopyFrom(src *Set[T]) {
+	clear(s.list)
+	for v := range src.list {
+		s.list[v] = struct{}{}
+	}
+}
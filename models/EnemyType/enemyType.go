@@ -0,0 +1,9 @@
+package EnemyType
+
+type EnemyType int
+
+const (
+	One   EnemyType = iota
+	Two   EnemyType = iota
+	Three EnemyType = iota
+)
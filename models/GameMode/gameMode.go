@@ -0,0 +1,10 @@
+package GameMode
+
+type GameMode int
+
+const (
+	Single      GameMode = iota
+	Alternating GameMode = iota
+	Coop        GameMode = iota
+	Versus      GameMode = iota
+)
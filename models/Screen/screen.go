@@ -6,4 +6,9 @@ const (
 	Menu     Screen = iota
 	Play     Screen = iota
 	GameOver Screen = iota
+	Settings Screen = iota
+	Turn     Screen = iota
+	Online   Screen = iota
+	Spectate Screen = iota
+	Display  Screen = iota
 )
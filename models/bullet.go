@@ -1,9 +1,14 @@
 package models
 
-import "math"
+// Bullets passing within this many pixels of each other sideways collide.
+const BulletHitWidth = 4
 
 type Bullet struct {
-	Position  Position
+	Position Position
+	// Where the bullet was before its last move. Collisions are checked
+	// along the whole way from there, so fast bullets can't skip past thin
+	// targets.
+	Previous  Position
 	Direction int
 	Speed     int
 	IsActive  bool
@@ -16,20 +21,38 @@ func (b *Bullet) Fire() {
 	}
 }
 
-func (b *Bullet) HasCollided(leftEdge, rightEdge, topEdge, bottomEdge float64) bool {
-	var bulletPositionY = b.Position.Y
+func (b *Bullet) Move() {
+	b.Previous = b.Position
+	b.Position.Y += float64(b.Speed * b.Direction)
+}
+
+// Path returns where the leading end of the bullet was before and after its
+// last move: the top of a bullet going up, the bottom of one coming down.
+func (b *Bullet) Path() (Position, Position) {
+	var from, to = b.Previous, b.Position
 	if b.Direction > 0 {
-		bulletPositionY += b.Height
+		from.Y += b.Height
+		to.Y += b.Height
 	}
-	return b.Position.X >= leftEdge && b.Position.X <= rightEdge &&
-		bulletPositionY <= bottomEdge && bulletPositionY >= topEdge
+	return from, to
+}
+
+// CollisionTime reports whether the bullet ran into the box during its last
+// move, and how far through the move, from 0 to 1, it got there. A bullet
+// stops at the first thing it hits.
+func (b *Bullet) CollisionTime(leftEdge, rightEdge, topEdge, bottomEdge float64) (float64, bool) {
+	var from, to = b.Path()
+	return SegmentHitsBox(from, to, leftEdge, rightEdge, topEdge, bottomEdge)
 }
 
-func (b *Bullet) HasCollidedBullets(otherBullet Bullet) bool {
-	var playerBulletX = b.Position.X
-	var enemyBulletX = otherBullet.Position.X
-	var playerBulletTopEdge = int(b.Position.Y)
-	var enemyBulletTopEdge = int(otherBullet.Position.Y)
-	var enemyBulletBottomEdge = int(otherBullet.Position.Y + otherBullet.Height)
-	return math.Abs(playerBulletX-enemyBulletX) <= 4 && playerBulletTopEdge <= enemyBulletBottomEdge && playerBulletTopEdge >= enemyBulletTopEdge
+// BulletCollisionTime looks at the move from otherBullet's point of view, so
+// that two bullets flying at each other can't pass through one another
+// between ticks.
+func (b *Bullet) BulletCollisionTime(otherBullet Bullet) (float64, bool) {
+	var from, to = b.Path()
+	from.X += otherBullet.Position.X - otherBullet.Previous.X
+	from.Y += otherBullet.Position.Y - otherBullet.Previous.Y
+	return SegmentHitsBox(from, to,
+		otherBullet.Position.X-BulletHitWidth, otherBullet.Position.X+BulletHitWidth,
+		otherBullet.Position.Y, otherBullet.Position.Y+otherBullet.Height)
 }
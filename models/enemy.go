@@ -1,21 +1,27 @@
 package models
 
 import (
+	"github.com/akshayxml/spaders/models/EnemyType"
 	"github.com/akshayxml/spaders/models/EntityState"
-	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Size of the enemy images in ./assets, before scaling.
+const (
+	EnemyImgWidth  = 40
+	EnemyImgHeight = 32
 )
 
 type Enemy struct {
 	Position Position
-	Img      *ebiten.Image
+	Type     EnemyType.EnemyType
 	Scale    float64
 	State    EntityState.EntityState
 }
 
 func (e *Enemy) GetEnemyWidth() float64 {
-	return float64(e.Img.Bounds().Dx()) * e.Scale
+	return EnemyImgWidth * e.Scale
 }
 
 func (e *Enemy) GetEnemyHeight() float64 {
-	return float64(e.Img.Bounds().Dy()) * e.Scale
+	return EnemyImgHeight * e.Scale
 }
@@ -4,7 +4,5 @@ type EnemyState struct {
 	EnemyCount          int
 	HorizontalDirection int
 	HorizontalSpeed     float64
-	BulletCount         int
 	EnemyFireRate       int
-	EnemyBullets        []Bullet
 }
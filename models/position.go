@@ -3,3 +3,23 @@ package models
 type Position struct {
 	X, Y float64
 }
+
+// SegmentHitsBox reports whether any point of the segment from a to b lies
+// in the box, edges included, and how far along the segment it first does,
+// from 0 at a to 1 at b. A segment with no length is just a point.
+func SegmentHitsBox(a, b Position, left, right, top, bottom float64) (float64, bool) {
+	var enter, leave = 0.0, 1.0
+	var clip = func(start, delta, low, high float64) bool {
+		if delta == 0 {
+			return start >= low && start <= high
+		}
+		var t0, t1 = (low - start) / delta, (high - start) / delta
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		enter, leave = max(enter, t0), min(leave, t1)
+		return enter <= leave
+	}
+	var hit = clip(a.X, b.X-a.X, left, right) && clip(a.Y, b.Y-a.Y, top, bottom)
+	return enter, hit
+}
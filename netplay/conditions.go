@@ -0,0 +1,44 @@
+package netplay
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Conditions simulate a bad network on top of a real connection, which makes
+// it possible to try rollback on localhost. Only input and checksum messages
+// are affected; the session tolerates losing those.
+type Conditions struct {
+	Latency time.Duration
+	Jitter  time.Duration
+	Loss    float64
+}
+
+func (c Conditions) isPerfect() bool {
+	return c.Latency == 0 && c.Jitter == 0 && c.Loss == 0
+}
+
+func (c Conditions) delay() time.Duration {
+	var delay = c.Latency
+	if c.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(2*c.Jitter))) - c.Jitter
+	}
+	return max(delay, 0)
+}
+
+func (s *Session) SetConditions(conditions Conditions) {
+	s.conditions = conditions
+}
+
+func (s *Session) sendUnreliable(msg message) {
+	if s.conditions.isPerfect() {
+		s.send(msg)
+		return
+	}
+	if rand.Float64() < s.conditions.Loss {
+		return
+	}
+	time.AfterFunc(s.conditions.delay(), func() {
+		s.send(msg)
+	})
+}
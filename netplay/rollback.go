@@ -0,0 +1,185 @@
+package netplay
+
+import "github.com/akshayxml/spaders/simulation"
+
+const (
+	MaxRollbackTicks = 8
+	ResendInterval   = 10
+)
+
+// Simulation is what Rollback drives. Save and Load store and restore the
+// full state in one of MaxRollbackTicks+1 slots.
+type Simulation interface {
+	Step(inputs []simulation.Input)
+	Save(slot int)
+	Load(slot int)
+	Checksum() uint64
+}
+
+// Rollback runs a Simulation ahead of the remote player by predicting that
+// they keep pressing whatever they pressed last. When their real input
+// arrives and differs, the simulation is restored to the mispredicted tick
+// and simulated forward again.
+type Rollback struct {
+	session   *Session
+	sim       Simulation
+	tick      int64
+	confirmed int64
+	predicted map[int64]simulation.Input
+	checksums map[int64]uint64
+	lastKnown simulation.Input
+	stalled   int
+	Rollbacks int
+
+	// OnStep, if set, is called after every step of the simulation,
+	// including the steps taken again after a misprediction.
+	OnStep func(tick int64)
+	// OnConfirm, if set, is called with the final inputs of every tick once
+	// they are known, in order, and after the simulation has been stepped
+	// with them. What the tick's last step did is then final.
+	OnConfirm func(tick int64, inputs []simulation.Input)
+}
+
+func NewRollback(session *Session, sim Simulation) *Rollback {
+	return &Rollback{
+		session:   session,
+		sim:       sim,
+		predicted: map[int64]simulation.Input{},
+		checksums: map[int64]uint64{},
+	}
+}
+
+func slot(tick int64) int {
+	return int(tick % (MaxRollbackTicks + 1))
+}
+
+func (r *Rollback) Tick() int64 {
+	return r.tick
+}
+
+// Stalled is the number of updates in a row that could not advance because
+// the remote player is too far behind.
+func (r *Rollback) Stalled() int {
+	return r.stalled
+}
+
+func (r *Rollback) NeedsInput() bool {
+	return r.session.NeedsInput(r.tick)
+}
+
+func (r *Rollback) inputs(tick int64) []simulation.Input {
+	var inputs = make([]simulation.Input, 2)
+	inputs[r.session.Local], _ = r.session.Input(r.session.Local, tick)
+	if in, ok := r.session.Input(r.session.Remote(), tick); ok {
+		inputs[r.session.Remote()] = in
+		r.lastKnown = in
+	} else {
+		r.predicted[tick] = r.lastKnown
+		inputs[r.session.Remote()] = r.lastKnown
+	}
+	return inputs
+}
+
+// confirm moves the confirmed tick past every tick whose remote input is now
+// known and returns the first of them that was mispredicted, or -1.
+func (r *Rollback) confirm() int64 {
+	var mispredicted int64 = -1
+	for r.confirmed < r.tick {
+		in, ok := r.session.Input(r.session.Remote(), r.confirmed)
+		if !ok {
+			break
+		}
+		if guess, wasPredicted := r.predicted[r.confirmed]; wasPredicted {
+			if guess != in && mispredicted < 0 {
+				mispredicted = r.confirmed
+			}
+			delete(r.predicted, r.confirmed)
+		}
+		r.confirmed++
+	}
+	return mispredicted
+}
+
+// announce calls OnConfirm for the ticks from from up to the confirmed
+// tick.
+func (r *Rollback) announce(from int64) {
+	if r.OnConfirm == nil {
+		return
+	}
+	for tick := from; tick < r.confirmed; tick++ {
+		var inputs = make([]simulation.Input, 2)
+		inputs[r.session.Local], _ = r.session.Input(r.session.Local, tick)
+		inputs[r.session.Remote()], _ = r.session.Input(r.session.Remote(), tick)
+		r.OnConfirm(tick, inputs)
+	}
+}
+
+func (r *Rollback) step(tick int64) {
+	r.sim.Step(r.inputs(tick))
+	if r.OnStep != nil {
+		r.OnStep(tick)
+	}
+}
+
+func (r *Rollback) save(tick int64) {
+	r.sim.Save(slot(tick))
+	if tick%ChecksumInterval == 0 {
+		r.checksums[tick] = r.sim.Checksum()
+	}
+}
+
+func (r *Rollback) resimulate(from int64) {
+	r.Rollbacks++
+	r.sim.Load(slot(from))
+	for tick := from; tick < r.tick; tick++ {
+		if tick != from {
+			r.save(tick)
+		}
+		r.step(tick)
+	}
+}
+
+// sendChecksums shares the checksum of every confirmed state, since only
+// those are final on both peers.
+func (r *Rollback) sendChecksums() {
+	for tick, checksum := range r.checksums {
+		if tick <= r.confirmed {
+			r.session.SendChecksum(tick, checksum)
+			delete(r.checksums, tick)
+		}
+	}
+}
+
+// Update sends the local input when one is due and advances the simulation
+// by at most one tick. It reports whether the simulation advanced.
+func (r *Rollback) Update(local simulation.Input) bool {
+	if r.NeedsInput() {
+		r.session.SendInput(local)
+	}
+	r.session.Poll()
+
+	var confirmed = r.confirmed
+	if mispredicted := r.confirm(); mispredicted >= 0 {
+		r.resimulate(mispredicted)
+	}
+	r.announce(confirmed)
+	r.sendChecksums()
+	r.session.Forget(r.confirmed - MaxRollbackTicks)
+
+	if r.tick-r.confirmed >= MaxRollbackTicks {
+		r.stalled++
+		if r.stalled%ResendInterval == 0 {
+			r.session.Resend()
+		}
+		return false
+	}
+	if _, ok := r.session.Input(r.session.Local, r.tick); !ok {
+		r.stalled++
+		return false
+	}
+	r.stalled = 0
+	r.save(r.tick)
+	r.step(r.tick)
+	r.tick++
+	return true
+}
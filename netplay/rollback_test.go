@@ -0,0 +1,105 @@
+package netplay
+
+import (
+	"testing"
+
+	"github.com/akshayxml/spaders/models/GameMode"
+	"github.com/akshayxml/spaders/simulation"
+)
+
+func TestRollbackOverLossyLink(t *testing.T) {
+	for _, mode := range []GameMode.GameMode{GameMode.Coop, GameMode.Versus} {
+		var settings = Settings{Seed: 3, Difficulty: 3, Mode: mode, InputDelay: 2, Rollback: true}
+		var sessions = pipeSessions(t, settings, lossy)
+		var rollbacks [2]*Rollback
+		var last, final [2]uint64
+		var confirmed [2]bool
+		for peer, s := range sessions {
+			peer := peer
+			var match = simulation.NewMatch(settings.Seed, settings.Difficulty, settings.Mode)
+			var r = NewRollback(s, match)
+			// The state after the last tick is only final once that tick is
+			// confirmed, whatever it was resimulated to before.
+			r.OnStep = func(tick int64) {
+				if tick == testTicks-1 {
+					last[peer] = match.Checksum()
+				}
+			}
+			r.OnConfirm = func(tick int64, inputs []simulation.Input) {
+				for player, in := range inputs {
+					if tick >= int64(settings.InputDelay) && in != testInput(player, tick) {
+						t.Errorf("peer %d confirmed input %d of player %d on tick %d, want %d", peer, in, player, tick, testInput(player, tick))
+					}
+				}
+				if tick == testTicks-1 {
+					final[peer], confirmed[peer] = last[peer], true
+				}
+			}
+			rollbacks[peer] = r
+		}
+
+		drive(t, sessions, func(peer int) {
+			var r = rollbacks[peer]
+			var in simulation.Input
+			if r.NeedsInput() {
+				in = testInput(r.session.Local, r.session.nextLocalTick)
+			}
+			r.Update(in)
+		}, func() bool {
+			return confirmed[Host] && confirmed[Guest]
+		})
+
+		var want = lockstepChecksum(settings, testTicks)
+		for peer, checksum := range final {
+			if checksum != want {
+				t.Errorf("%v: peer %d ended with checksum %x, want %x", mode, peer, checksum, want)
+			}
+		}
+		if rollbacks[Host].Rollbacks+rollbacks[Guest].Rollbacks == 0 {
+			t.Errorf("%v: no prediction was ever wrong, so resimulating was not tested", mode)
+		}
+	}
+}
+
+// A remote input that comes late but matches the prediction needs no
+// rollback, and the slots keep being reused past MaxRollbackTicks.
+func TestRollbackKeepsCorrectPredictions(t *testing.T) {
+	var settings = Settings{Seed: 5, Difficulty: 1, Mode: GameMode.Coop, InputDelay: 1, Rollback: true}
+	var s = pipeSessions(t, settings, Conditions{})[Host]
+	var match = simulation.NewMatch(settings.Seed, settings.Difficulty, settings.Mode)
+	var r = NewRollback(s, match)
+	var remote = s.Remote()
+	var steps int
+	r.OnStep = func(tick int64) { steps++ }
+
+	var ticks = int64(3 * MaxRollbackTicks)
+	for tick := int64(0); tick < ticks; tick++ {
+		var in simulation.Input
+		if r.NeedsInput() {
+			in = simulation.InputLeft
+		}
+		// The remote player presses nothing, which is what is predicted, and
+		// their inputs arrive a few ticks late.
+		if late := tick - MaxRollbackTicks/2; late >= int64(settings.InputDelay) {
+			s.inputs[remote][late] = 0
+		}
+		if !r.Update(in) {
+			t.Fatalf("stalled on tick %d", tick)
+		}
+	}
+	if r.Rollbacks != 0 || steps != int(ticks) {
+		t.Errorf("%d rollbacks and %d steps for %d ticks of correct predictions", r.Rollbacks, steps, ticks)
+	}
+
+	var want = simulation.NewMatch(settings.Seed, settings.Difficulty, settings.Mode)
+	for tick := int64(0); tick < ticks; tick++ {
+		var inputs = []simulation.Input{0, 0}
+		if tick >= int64(settings.InputDelay) {
+			inputs[Host] = simulation.InputLeft
+		}
+		want.Step(inputs)
+	}
+	if match.Checksum() != want.Checksum() {
+		t.Errorf("checksum %x, want %x", match.Checksum(), want.Checksum())
+	}
+}
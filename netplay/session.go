@@ -0,0 +1,319 @@
+package netplay
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/akshayxml/spaders/models/GameMode"
+	"github.com/akshayxml/spaders/simulation"
+)
+
+const (
+	ProtocolVersion   = 4
+	DefaultInputDelay = 3
+	MaxInputDelay     = 10
+	ChecksumInterval  = 30
+	Host              = 0
+	Guest             = 1
+	dialTimeout       = 10 * time.Second
+)
+
+var ErrDesync = errors.New("netplay: simulations have diverged")
+
+// Settings are chosen by the host and sent to the guest so that both sides
+// create identical worlds.
+type Settings struct {
+	Seed       int64
+	Difficulty int
+	Mode       GameMode.GameMode
+	InputDelay int
+	Rollback   bool
+}
+
+type messageKind int
+
+const (
+	helloMessage messageKind = iota
+	inputMessage
+	checksumMessage
+)
+
+// An input message carries the inputs for the ticks up to and including
+// Tick, starting from the first one the peer has not acknowledged, so that
+// lost messages are recovered by the next. Every message acknowledges the
+// remote inputs before Ack.
+type message struct {
+	Kind     messageKind
+	Version  int
+	Settings Settings
+	Tick     int64
+	Inputs   []simulation.Input
+	Checksum uint64
+	Ack      int64
+}
+
+// Session is one end of a lockstep connection. Every tick both peers send
+// the input they sampled InputDelay ticks earlier, and a tick is only
+// simulated once the inputs of both players have arrived.
+type Session struct {
+	Settings Settings
+	Local    int
+
+	conn            net.Conn
+	encoder         *gob.Encoder
+	incoming        chan message
+	inputs          [2]map[int64]simulation.Input
+	localChecksums  map[int64]uint64
+	remoteChecksums map[int64]uint64
+	nextLocalTick   int64
+	// The local inputs the peer has not acknowledged, from the tick sentFrom
+	// on.
+	sent     []simulation.Input
+	sentFrom int64
+	// Every remote input before received has arrived.
+	received   int64
+	conditions Conditions
+
+	mu     sync.Mutex
+	sendMu sync.Mutex
+	err    error
+}
+
+func newSession(conn net.Conn, local int, settings Settings) *Session {
+	var s = &Session{
+		Settings:        settings,
+		Local:           local,
+		conn:            conn,
+		encoder:         gob.NewEncoder(conn),
+		incoming:        make(chan message, 256),
+		localChecksums:  map[int64]uint64{},
+		remoteChecksums: map[int64]uint64{},
+		nextLocalTick:   int64(settings.InputDelay),
+		sentFrom:        int64(settings.InputDelay),
+		received:        int64(settings.InputDelay),
+	}
+	s.inputs[Host] = map[int64]simulation.Input{}
+	s.inputs[Guest] = map[int64]simulation.Input{}
+	return s
+}
+
+func Listen(addr string) (net.Listener, error) {
+	return net.Listen("tcp", addr)
+}
+
+// Accept waits for a guest to connect and starts a session with it. Closing
+// the listener from another goroutine cancels the wait.
+func Accept(listener net.Listener, settings Settings) (*Session, error) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	var s = newSession(conn, Host, settings)
+	if err := s.send(message{Kind: helloMessage, Version: ProtocolVersion, Settings: settings}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go s.read(gob.NewDecoder(conn))
+	return s, nil
+}
+
+// Dial connects to a host and adopts the settings it sends.
+func Dial(addr string) (*Session, error) {
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	var decoder = gob.NewDecoder(conn)
+	var hello message
+	if err := decoder.Decode(&hello); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if hello.Kind != helloMessage || hello.Version != ProtocolVersion {
+		conn.Close()
+		return nil, fmt.Errorf("netplay: host speaks protocol %d, want %d", hello.Version, ProtocolVersion)
+	}
+
+	var s = newSession(conn, Guest, hello.Settings)
+	go s.read(decoder)
+	return s, nil
+}
+
+func (s *Session) read(decoder *gob.Decoder) {
+	for {
+		var msg message
+		if err := decoder.Decode(&msg); err != nil {
+			s.fail(err)
+			close(s.incoming)
+			return
+		}
+		s.incoming <- msg
+	}
+}
+
+func (s *Session) send(msg message) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	if err := s.encoder.Encode(msg); err != nil {
+		s.fail(err)
+		return err
+	}
+	return nil
+}
+
+func (s *Session) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *Session) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *Session) Remote() int {
+	return 1 - s.Local
+}
+
+// NeedsInput reports whether the local input for the tick after tick+delay
+// still has to be sent. It keeps the local player exactly InputDelay ticks
+// ahead of the simulation.
+func (s *Session) NeedsInput(tick int64) bool {
+	return s.nextLocalTick <= tick+int64(s.Settings.InputDelay)
+}
+
+func (s *Session) SendInput(in simulation.Input) {
+	s.inputs[s.Local][s.nextLocalTick] = in
+	s.nextLocalTick++
+	s.sent = append(s.sent, in)
+	s.sendInputs()
+}
+
+func (s *Session) sendInputs() {
+	s.sendUnreliable(message{Kind: inputMessage, Tick: s.nextLocalTick - 1, Inputs: append([]simulation.Input{}, s.sent...), Ack: s.received})
+}
+
+// acknowledged drops the local inputs the peer has confirmed receiving.
+func (s *Session) acknowledged(ack int64) {
+	if ack <= s.sentFrom {
+		return
+	}
+	s.sent = s.sent[min(ack-s.sentFrom, int64(len(s.sent))):]
+	s.sentFrom = ack
+}
+
+// Poll stores every message that has arrived since the last call.
+func (s *Session) Poll() {
+	for {
+		select {
+		case msg, ok := <-s.incoming:
+			if !ok {
+				return
+			}
+			s.acknowledged(msg.Ack)
+			switch msg.Kind {
+			case inputMessage:
+				for i, in := range msg.Inputs {
+					var tick = msg.Tick - int64(len(msg.Inputs)-1-i)
+					if tick >= s.received {
+						s.inputs[s.Remote()][tick] = in
+					}
+				}
+				for {
+					if _, ok := s.inputs[s.Remote()][s.received]; !ok {
+						break
+					}
+					s.received++
+				}
+			case checksumMessage:
+				s.remoteChecksums[msg.Tick] = msg.Checksum
+				s.compareChecksums(msg.Tick)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// Inputs returns the inputs of both players for tick, indexed by player, or
+// false while the remote input has not arrived yet.
+func (s *Session) Inputs(tick int64) ([]simulation.Input, bool) {
+	s.Poll()
+	var inputs = make([]simulation.Input, 2)
+	for player := range inputs {
+		in, ok := s.Input(player, tick)
+		if !ok {
+			return nil, false
+		}
+		inputs[player] = in
+	}
+	s.Forget(tick + 1)
+	return inputs, true
+}
+
+// Input returns the input of player for tick if it is known. Nobody has
+// input during the first InputDelay ticks.
+func (s *Session) Input(player int, tick int64) (simulation.Input, bool) {
+	if tick < int64(s.Settings.InputDelay) {
+		return 0, true
+	}
+	in, ok := s.inputs[player][tick]
+	return in, ok
+}
+
+// Forget drops the inputs of every tick before tick.
+func (s *Session) Forget(tick int64) {
+	for _, inputs := range s.inputs {
+		for t := range inputs {
+			if t < tick {
+				delete(inputs, t)
+			}
+		}
+	}
+}
+
+// SendChecksum shares the checksum of the state after tick with the peer.
+func (s *Session) SendChecksum(tick int64, checksum uint64) {
+	s.localChecksums[tick] = checksum
+	s.sendUnreliable(message{Kind: checksumMessage, Tick: tick, Checksum: checksum, Ack: s.received})
+	s.compareChecksums(tick)
+}
+
+func (s *Session) compareChecksums(tick int64) {
+	local, ok := s.localChecksums[tick]
+	if !ok {
+		return
+	}
+	remote, ok := s.remoteChecksums[tick]
+	if !ok {
+		return
+	}
+	if local != remote {
+		s.fail(fmt.Errorf("%w at tick %d", ErrDesync, tick))
+	}
+	delete(s.localChecksums, tick)
+	delete(s.remoteChecksums, tick)
+}
+
+func (s *Session) Close() error {
+	return s.conn.Close()
+}
+
+// Resend repeats every local input the peer has not acknowledged, in case
+// the messages carrying them were lost while both peers are waiting on each
+// other.
+func (s *Session) Resend() {
+	if len(s.sent) == 0 {
+		return
+	}
+	s.sendInputs()
+}
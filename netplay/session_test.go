@@ -0,0 +1,138 @@
+package netplay
+
+import (
+	"encoding/gob"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/akshayxml/spaders/models/GameMode"
+	"github.com/akshayxml/spaders/simulation"
+)
+
+const testTicks = 300
+
+// lossy is a network bad enough to lose, delay and reorder plenty of
+// messages on the way.
+var lossy = Conditions{Latency: 4 * time.Millisecond, Jitter: 3 * time.Millisecond, Loss: 0.6}
+
+// pipeSessions connects a host and a guest over an in-memory connection.
+func pipeSessions(t *testing.T, settings Settings, conditions Conditions) [2]*Session {
+	var hostConn, guestConn = net.Pipe()
+	var sessions = [2]*Session{
+		newSession(hostConn, Host, settings),
+		newSession(guestConn, Guest, settings),
+	}
+	for _, s := range sessions {
+		s := s
+		s.SetConditions(conditions)
+		go s.read(gob.NewDecoder(s.conn))
+		t.Cleanup(func() { s.Close() })
+	}
+	return sessions
+}
+
+// testInput is what player presses on tick. It changes every few ticks so
+// that rollback has to guess wrong now and then.
+func testInput(player int, tick int64) simulation.Input {
+	return simulation.Input((tick/5*7 + int64(player)*3 + tick/11) % 8)
+}
+
+// lockstepChecksum is the checksum of a match stepped ticks times with the
+// test inputs, which every netplay run must end up with.
+func lockstepChecksum(settings Settings, ticks int64) uint64 {
+	var match = simulation.NewMatch(settings.Seed, settings.Difficulty, settings.Mode)
+	var inputs = make([]simulation.Input, 2)
+	for tick := int64(0); tick < ticks; tick++ {
+		for player := range inputs {
+			inputs[player] = 0
+			if tick >= int64(settings.InputDelay) {
+				inputs[player] = testInput(player, tick)
+			}
+		}
+		match.Step(inputs)
+	}
+	return match.Checksum()
+}
+
+// drive calls update for both peers, as the game does once a frame, until
+// done says both are finished.
+func drive(t *testing.T, sessions [2]*Session, update func(peer int), done func() bool) {
+	var deadline = time.Now().Add(30 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatal("peers stalled")
+		}
+		for peer, s := range sessions {
+			if err := s.Err(); err != nil {
+				t.Fatalf("peer %d: %v", peer, err)
+			}
+			update(peer)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLockstepOverLossyLink(t *testing.T) {
+	var settings = Settings{Seed: 7, Difficulty: 2, Mode: GameMode.Coop, InputDelay: MaxInputDelay}
+	var sessions = pipeSessions(t, settings, lossy)
+	var matches, ticks, stalled, checksums = [2]*simulation.Match{}, [2]int64{}, [2]int{}, [2]uint64{}
+	for peer := range matches {
+		matches[peer] = simulation.NewMatch(settings.Seed, settings.Difficulty, settings.Mode)
+	}
+
+	drive(t, sessions, func(peer int) {
+		var s = sessions[peer]
+		if s.NeedsInput(ticks[peer]) {
+			s.SendInput(testInput(s.Local, s.nextLocalTick))
+		}
+		inputs, ok := s.Inputs(ticks[peer])
+		if !ok {
+			stalled[peer]++
+			if stalled[peer]%ResendInterval == 0 {
+				s.Resend()
+			}
+			return
+		}
+		stalled[peer] = 0
+		matches[peer].Step(inputs)
+		ticks[peer]++
+		if ticks[peer]%ChecksumInterval == 0 {
+			s.SendChecksum(ticks[peer], matches[peer].Checksum())
+		}
+		if ticks[peer] == testTicks {
+			checksums[peer] = matches[peer].Checksum()
+		}
+	}, func() bool {
+		return ticks[Host] >= testTicks && ticks[Guest] >= testTicks
+	})
+
+	var want = lockstepChecksum(settings, testTicks)
+	for peer, checksum := range checksums {
+		if checksum != want {
+			t.Errorf("peer %d ended with checksum %x, want %x", peer, checksum, want)
+		}
+	}
+}
+
+func TestResendOnlyUnacknowledgedInputs(t *testing.T) {
+	var settings = Settings{Seed: 1, Difficulty: 1, Mode: GameMode.Coop, InputDelay: 2}
+	var s = newSession(nil, Host, settings)
+	for tick := 0; tick < 20; tick++ {
+		s.inputs[s.Local][s.nextLocalTick] = simulation.Input(tick)
+		s.nextLocalTick++
+		s.sent = append(s.sent, simulation.Input(tick))
+	}
+	s.acknowledged(7)
+	if s.sentFrom != 7 || len(s.sent) != 15 || s.sent[0] != 5 {
+		t.Errorf("after ack 7: inputs from %d, %d of them starting with %d, want from 7, 15 starting with 5", s.sentFrom, len(s.sent), s.sent[0])
+	}
+	s.acknowledged(3)
+	if s.sentFrom != 7 {
+		t.Errorf("an older ack moved the unacknowledged inputs back to %d", s.sentFrom)
+	}
+	s.acknowledged(22)
+	if len(s.sent) != 0 {
+		t.Errorf("%d inputs left after everything was acknowledged", len(s.sent))
+	}
+}
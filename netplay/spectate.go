@@ -0,0 +1,291 @@
+package netplay
+
+import (
+	"encoding/gob"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/akshayxml/spaders/models/GameMode"
+	"github.com/akshayxml/spaders/save"
+	"github.com/akshayxml/spaders/simulation"
+)
+
+const (
+	// A spectator that falls this many messages behind is dropped rather
+	// than slowing down the game.
+	spectatorBacklog = 1024
+	// How many frames go by before the history is replaced by the state of
+	// the worlds, which is all a late spectator needs to catch up.
+	checkpointFrames = 10 * simulation.TicksPerSecond
+)
+
+// Setup describes the match created from settings.
+func (settings Settings) Setup() simulation.Setup {
+	if settings.Mode == GameMode.Versus {
+		return simulation.Setup{Mode: settings.Mode, Difficulty: settings.Difficulty, Seeds: []int64{settings.Seed, settings.Seed}, Players: 1}
+	}
+	return simulation.Setup{Mode: settings.Mode, Difficulty: settings.Difficulty, Seeds: []int64{settings.Seed}, Players: 2}
+}
+
+type streamMessageKind int
+
+const (
+	streamStart streamMessageKind = iota
+	streamFrame
+)
+
+type streamMessage struct {
+	Kind    streamMessageKind
+	Version int
+	Setup   simulation.Setup
+	Worlds  []save.World
+	Turn    int
+	Frame   simulation.Frame
+}
+
+// Broadcast streams the inputs of the game being played to any number of
+// read-only spectators. Spectators that join late are sent the latest
+// checkpoint and the frames since, so it keeps its own copy of the worlds
+// stepped by the streamed frames to take checkpoints from.
+type Broadcast struct {
+	listener net.Listener
+
+	mu      sync.Mutex
+	history []streamMessage
+	viewers []chan streamMessage
+	setup   simulation.Setup
+	worlds  []*simulation.World
+	turn    int
+}
+
+func NewBroadcast(addr string) (*Broadcast, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	var b = &Broadcast{listener: listener}
+	go b.accept()
+	return b, nil
+}
+
+func (b *Broadcast) accept() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		var backlog = append([]streamMessage{}, b.history...)
+		var outgoing = make(chan streamMessage, spectatorBacklog)
+		b.viewers = append(b.viewers, outgoing)
+		b.mu.Unlock()
+		go writeStream(conn, backlog, outgoing)
+	}
+}
+
+func writeStream(conn net.Conn, backlog []streamMessage, outgoing chan streamMessage) {
+	defer conn.Close()
+	var encoder = gob.NewEncoder(conn)
+	for _, msg := range backlog {
+		if err := encoder.Encode(msg); err != nil {
+			return
+		}
+	}
+	for msg := range outgoing {
+		if err := encoder.Encode(msg); err != nil {
+			return
+		}
+	}
+}
+
+// publish must be called with b.mu held.
+func (b *Broadcast) publish(msg streamMessage) {
+	if msg.Kind == streamStart {
+		b.history = nil
+	}
+	b.history = append(b.history, msg)
+
+	var viewers = b.viewers[:0]
+	for _, outgoing := range b.viewers {
+		select {
+		case outgoing <- msg:
+			viewers = append(viewers, outgoing)
+		default:
+			close(outgoing)
+		}
+	}
+	b.viewers = viewers
+}
+
+// Start tells spectators that a game has begun, with the worlds as they are
+// now, which for a resumed game is partway through, and the world whose
+// turn it is.
+func (b *Broadcast) Start(setup simulation.Setup, worlds []*simulation.World, turn int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.setup, b.turn = setup, turn
+	b.worlds = b.worlds[:0]
+	for _, world := range worlds {
+		b.worlds = append(b.worlds, world.Clone())
+	}
+	b.publish(b.checkpoint())
+}
+
+func (b *Broadcast) checkpoint() streamMessage {
+	var msg = streamMessage{Kind: streamStart, Version: ProtocolVersion, Setup: b.setup, Turn: b.turn}
+	for _, world := range b.worlds {
+		msg.Worlds = append(msg.Worlds, save.FromWorld(world))
+	}
+	return msg
+}
+
+// Frame streams the inputs of one tick, indexed by world.
+func (b *Broadcast) Frame(frame simulation.Frame) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var copied = make(simulation.Frame, len(frame))
+	for i, inputs := range frame {
+		copied[i] = append([]simulation.Input{}, inputs...)
+		if len(inputs) > 0 && i < len(b.worlds) {
+			b.worlds[i].Step(inputs)
+			b.turn = i
+		}
+	}
+	b.publish(streamMessage{Kind: streamFrame, Frame: copied})
+	// Spectators already watching have every frame, so only the history is
+	// cut back.
+	if len(b.history) > checkpointFrames && len(b.worlds) > 0 {
+		b.history = []streamMessage{b.checkpoint()}
+	}
+}
+
+func (b *Broadcast) Viewers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.viewers)
+}
+
+func (b *Broadcast) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, outgoing := range b.viewers {
+		close(outgoing)
+	}
+	b.viewers = nil
+	return b.listener.Close()
+}
+
+// Spectator follows a broadcast game by simulating the streamed inputs. Until
+// the first game starts Worlds is empty.
+type Spectator struct {
+	Setup  simulation.Setup
+	Worlds []*simulation.World
+	// Turn is the world that stepped last, which in alternating mode is the
+	// player whose turn it is.
+	Turn int
+	// OnStep, if set, is called after every tick of every world, with the
+	// world's events of that tick still in it.
+	OnStep func(w *simulation.World)
+
+	conn     net.Conn
+	incoming chan streamMessage
+
+	mu  sync.Mutex
+	err error
+}
+
+func Spectate(addr string) (*Spectator, error) {
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	var s = &Spectator{conn: conn, incoming: make(chan streamMessage, 256)}
+	go s.read(gob.NewDecoder(conn))
+	return s, nil
+}
+
+func (s *Spectator) read(decoder *gob.Decoder) {
+	for {
+		var msg streamMessage
+		if err := decoder.Decode(&msg); err != nil {
+			s.fail(err)
+			close(s.incoming)
+			return
+		}
+		s.incoming <- msg
+	}
+}
+
+func (s *Spectator) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *Spectator) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Update simulates every frame that has arrived since the last call. It
+// stops at the first message that can't be followed.
+func (s *Spectator) Update() {
+	for s.Err() == nil {
+		select {
+		case msg, ok := <-s.incoming:
+			if !ok {
+				return
+			}
+			s.apply(msg)
+		default:
+			return
+		}
+	}
+}
+
+func (s *Spectator) apply(msg streamMessage) {
+	switch msg.Kind {
+	case streamStart:
+		if msg.Version != ProtocolVersion {
+			s.fail(fmt.Errorf("netplay: broadcast speaks protocol %d, want %d", msg.Version, ProtocolVersion))
+			s.conn.Close()
+			return
+		}
+		if msg.Turn < 0 || msg.Turn >= len(msg.Worlds) {
+			s.fail(fmt.Errorf("netplay: broadcast started on turn %d of %d", msg.Turn, len(msg.Worlds)))
+			s.conn.Close()
+			return
+		}
+		s.Setup = msg.Setup
+		s.Worlds = s.Worlds[:0]
+		for _, saved := range msg.Worlds {
+			var world = saved.NewWorld()
+			world.TakesTurns = msg.Setup.Mode == GameMode.Alternating
+			s.Worlds = append(s.Worlds, world)
+		}
+		s.Turn = msg.Turn
+	case streamFrame:
+		if len(msg.Frame) != len(s.Worlds) {
+			s.fail(fmt.Errorf("netplay: broadcast sent a frame for %d worlds, watching %d", len(msg.Frame), len(s.Worlds)))
+			s.conn.Close()
+			return
+		}
+		for i, inputs := range msg.Frame {
+			if len(inputs) > 0 {
+				s.Worlds[i].Step(inputs)
+				s.Turn = i
+				if s.OnStep != nil {
+					s.OnStep(s.Worlds[i])
+				}
+			}
+		}
+	}
+}
+
+func (s *Spectator) Close() error {
+	return s.conn.Close()
+}
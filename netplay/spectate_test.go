@@ -0,0 +1,84 @@
+package netplay
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/akshayxml/spaders/models/GameMode"
+	"github.com/akshayxml/spaders/simulation"
+)
+
+func TestLateSpectatorCatchesUpFromCheckpoint(t *testing.T) {
+	b, err := NewBroadcast("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	var settings = Settings{Seed: 9, Difficulty: 1, Mode: GameMode.Coop}
+	var match = simulation.NewMatch(settings.Seed, settings.Difficulty, settings.Mode)
+	b.Start(settings.Setup(), match.Worlds, 0)
+	var tick int64
+	var step = func() {
+		var inputs = []simulation.Input{testInput(Host, tick), testInput(Guest, tick)}
+		match.Step(inputs)
+		b.Frame(match.WorldInputs(inputs))
+		tick++
+	}
+	for tick < 3*checkpointFrames+10 {
+		step()
+	}
+	b.mu.Lock()
+	var history = len(b.history)
+	b.mu.Unlock()
+	if history > checkpointFrames+1 {
+		t.Errorf("history holds %d messages after %d frames", history, tick)
+	}
+
+	s, err := Spectate(b.listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	var deadline = time.Now().Add(10 * time.Second)
+	for len(s.Worlds) == 0 || s.Worlds[0].Tick < match.Worlds[0].Tick {
+		if time.Now().After(deadline) {
+			t.Fatal("spectator never caught up")
+		}
+		if b.Viewers() > 0 && tick < 4*checkpointFrames {
+			step()
+		}
+		s.Update()
+		if err := s.Err(); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got, want := s.Worlds[0].Checksum(), match.Worlds[0].Checksum(); got != want {
+		t.Errorf("spectator checksum %x, want %x", got, want)
+	}
+}
+
+// nopConn stands in for the connection of a spectator fed by hand.
+type nopConn struct{ net.Conn }
+
+func (nopConn) Close() error { return nil }
+
+func TestSpectatorFailsOnMismatchedFrame(t *testing.T) {
+	var settings = Settings{Seed: 9, Difficulty: 1, Mode: GameMode.Versus}
+	var s = &Spectator{conn: nopConn{}, incoming: make(chan streamMessage, 4)}
+	var b = &Broadcast{}
+	b.setup = settings.Setup()
+	b.worlds = simulation.NewMatch(settings.Seed, settings.Difficulty, settings.Mode).Worlds
+	s.incoming <- b.checkpoint()
+	s.incoming <- streamMessage{Kind: streamFrame, Frame: simulation.Frame{{simulation.InputFire}}}
+	s.incoming <- streamMessage{Kind: streamFrame, Frame: simulation.Frame{{0}, {0}}}
+	s.Update()
+	if s.Err() == nil {
+		t.Fatal("a frame for one world was accepted while watching two")
+	}
+	if s.Worlds[0].Tick != 0 {
+		t.Errorf("spectator went on to tick %d after failing", s.Worlds[0].Tick)
+	}
+}
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/akshayxml/spaders/events"
+	"github.com/akshayxml/spaders/input"
+	"github.com/akshayxml/spaders/models"
+	"github.com/akshayxml/spaders/models/GameMode"
+	"github.com/akshayxml/spaders/models/Screen"
+	"github.com/akshayxml/spaders/netplay"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/theme"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	defaultOnlineAddress = "127.0.0.1:7777"
+	stallWarningTicks    = 30
+)
+
+var onlineMenuItems = []string{"HOST", "JOIN", "ADDRESS", "INPUT DELAY", "MODE", "NETCODE"}
+
+const (
+	hostOnlineItem = iota
+	joinOnlineItem
+	addressOnlineItem
+	inputDelayOnlineItem
+	modeOnlineItem
+	netcodeOnlineItem
+)
+
+var onlineGameModes = []GameMode.GameMode{GameMode.Coop, GameMode.Versus}
+
+type connectResult struct {
+	session *netplay.Session
+	err     error
+}
+
+type onlineMenuState struct {
+	selection  int
+	address    string
+	inputDelay int
+	mode       GameMode.GameMode
+	rollback   bool
+	conditions netplay.Conditions
+	editing    bool
+	status     string
+	listener   net.Listener
+	connecting chan connectResult
+}
+
+// onlineGame runs an online match either in plain lockstep or with
+// rollback. In versus each player only sees the rival's score.
+type onlineGame struct {
+	session     *netplay.Session
+	match       *simulation.Match
+	rollback    *netplay.Rollback
+	tick        int64
+	pendingFire bool
+	stalled     int
+	broadcast   *netplay.Broadcast
+	events      *events.Bus
+	// The events of the local world on each tick that has not been
+	// confirmed yet, with rollback.
+	tickEvents [netplay.MaxRollbackTicks + 1][]events.Event
+}
+
+func (g *Game) hostOnline() {
+	_, port, err := net.SplitHostPort(g.onlineMenu.address)
+	if err != nil {
+		g.onlineMenu.status = "BAD ADDRESS"
+		return
+	}
+	listener, err := netplay.Listen(":" + port)
+	if err != nil {
+		g.onlineMenu.status = "CANNOT LISTEN ON " + port
+		return
+	}
+	var settings = netplay.Settings{
+		Seed:       time.Now().UnixNano(),
+		Difficulty: g.difficulty,
+		Mode:       g.onlineMenu.mode,
+		InputDelay: g.onlineMenu.inputDelay,
+		Rollback:   g.onlineMenu.rollback,
+	}
+	var result = make(chan connectResult, 1)
+	go func() {
+		session, err := netplay.Accept(listener, settings)
+		result <- connectResult{session, err}
+	}()
+	g.onlineMenu.listener = listener
+	g.onlineMenu.connecting = result
+	g.onlineMenu.status = "WAITING FOR PLAYER ON PORT " + port
+}
+
+func (g *Game) joinOnline() {
+	var address = g.onlineMenu.address
+	var result = make(chan connectResult, 1)
+	go func() {
+		session, err := netplay.Dial(address)
+		result <- connectResult{session, err}
+	}()
+	g.onlineMenu.connecting = result
+	g.onlineMenu.status = "CONNECTING"
+}
+
+func (g *Game) cancelConnecting() {
+	if g.onlineMenu.listener != nil {
+		g.onlineMenu.listener.Close()
+		g.onlineMenu.listener = nil
+	}
+	g.onlineMenu.connecting = nil
+	g.onlineMenu.status = ""
+}
+
+func (g *Game) startOnline(session *netplay.Session) {
+	var settings = session.Settings
+	g.difficulty = settings.Difficulty
+	g.mode = settings.Mode
+	g.reset()
+
+	session.SetConditions(g.onlineMenu.conditions)
+	var online = &onlineGame{
+		session:   session,
+		match:     simulation.NewMatch(settings.Seed, settings.Difficulty, settings.Mode),
+		broadcast: g.broadcast,
+		events:    g.events,
+	}
+	if settings.Rollback {
+		online.rollback = netplay.NewRollback(session, online.match)
+		online.rollback.OnStep = func(tick int64) {
+			var slot = tick % int64(len(online.tickEvents))
+			online.tickEvents[slot] = append(online.tickEvents[slot][:0], online.localWorld().Events...)
+		}
+		online.rollback.OnConfirm = func(tick int64, inputs []simulation.Input) {
+			online.events.PublishAll(online.tickEvents[tick%int64(len(online.tickEvents))])
+			online.broadcastFrame(inputs)
+		}
+	}
+	g.startBroadcast(settings.Setup(), online.match.Worlds, 0)
+	g.world = online.localWorld()
+	g.turns = []*simulation.World{g.world}
+	g.stats = make([]turnStats, len(g.turns))
+	g.online = online
+	g.screen = Screen.Play
+}
+
+func (g *Game) closeOnline() {
+	if g.online != nil {
+		g.online.session.Close()
+		g.online = nil
+		g.mode = GameMode.Single
+	}
+}
+
+func (g *Game) updateOnlineMenu() {
+	if g.onlineMenu.connecting != nil {
+		select {
+		case result := <-g.onlineMenu.connecting:
+			g.onlineMenu.connecting = nil
+			g.onlineMenu.listener = nil
+			if result.err != nil {
+				g.onlineMenu.status = "CONNECTION FAILED"
+				return
+			}
+			g.onlineMenu.status = ""
+			g.startOnline(result.session)
+		default:
+			if g.actions.IsJustPressed(input.Back) {
+				g.cancelConnecting()
+			}
+		}
+		return
+	}
+
+	if g.onlineMenu.editing {
+		g.onlineMenu.address = string(ebiten.AppendInputChars([]rune(g.onlineMenu.address)))
+		if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(g.onlineMenu.address) > 0 {
+			g.onlineMenu.address = g.onlineMenu.address[:len(g.onlineMenu.address)-1]
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			g.onlineMenu.editing = false
+		}
+		return
+	}
+
+	if g.actions.IsJustPressed(input.Back) {
+		g.onlineMenu.status = ""
+		g.screen = Screen.Menu
+		return
+	}
+	if g.actions.IsJustPressed(input.MenuDown) {
+		g.onlineMenu.selection = (g.onlineMenu.selection + 1) % len(onlineMenuItems)
+	}
+	if g.actions.IsJustPressed(input.MenuUp) {
+		g.onlineMenu.selection = (g.onlineMenu.selection + len(onlineMenuItems) - 1) % len(onlineMenuItems)
+	}
+
+	switch g.onlineMenu.selection {
+	case inputDelayOnlineItem:
+		if g.actions.IsJustPressed(input.MoveLeft) {
+			g.onlineMenu.inputDelay = max(g.onlineMenu.inputDelay-1, 0)
+		}
+		if g.actions.IsJustPressed(input.MoveRight) {
+			g.onlineMenu.inputDelay = min(g.onlineMenu.inputDelay+1, netplay.MaxInputDelay)
+		}
+	case modeOnlineItem:
+		if g.actions.IsJustPressed(input.MoveLeft) || g.actions.IsJustPressed(input.MoveRight) || g.actions.IsJustPressed(input.Confirm) {
+			if g.onlineMenu.mode == GameMode.Coop {
+				g.onlineMenu.mode = GameMode.Versus
+			} else {
+				g.onlineMenu.mode = GameMode.Coop
+			}
+		}
+	case netcodeOnlineItem:
+		if g.actions.IsJustPressed(input.MoveLeft) || g.actions.IsJustPressed(input.MoveRight) || g.actions.IsJustPressed(input.Confirm) {
+			g.onlineMenu.rollback = !g.onlineMenu.rollback
+		}
+	}
+
+	if g.actions.IsJustPressed(input.Confirm) {
+		switch g.onlineMenu.selection {
+		case hostOnlineItem:
+			g.hostOnline()
+		case joinOnlineItem:
+			g.joinOnline()
+		case addressOnlineItem:
+			g.onlineMenu.editing = true
+		}
+	}
+}
+
+func (g *Game) localOnlineInput() simulation.Input {
+	var in = (actionInput(g.actions) | g.pointerInput(g.localPlayer())) &^ simulation.InputFire
+	if g.online.pendingFire {
+		in |= simulation.InputFire
+		g.online.pendingFire = false
+	}
+	return in
+}
+
+func (g *Game) localPlayer() *models.Player {
+	var local = g.online.session.Local
+	return g.world.Players[g.online.match.Player(local)]
+}
+
+func (o *onlineGame) updateLockstep(local func() simulation.Input) {
+	if o.session.NeedsInput(o.tick) {
+		o.session.SendInput(local())
+	}
+
+	inputs, ok := o.session.Inputs(o.tick)
+	if !ok {
+		o.stalled++
+		if o.stalled%netplay.ResendInterval == 0 {
+			o.session.Resend()
+		}
+		return
+	}
+	o.stalled = 0
+	o.match.Step(inputs)
+	o.events.PublishAll(o.localWorld().Events)
+	o.broadcastFrame(inputs)
+	o.tick++
+
+	if o.tick%netplay.ChecksumInterval == 0 {
+		o.session.SendChecksum(o.tick, o.match.Checksum())
+	}
+}
+
+func (o *onlineGame) localWorld() *simulation.World {
+	return o.match.World(o.session.Local)
+}
+
+// broadcastFrame streams the final inputs of a tick. With rollback the
+// simulation runs ahead of them, so spectators lag a few ticks behind.
+func (o *onlineGame) broadcastFrame(inputs []simulation.Input) {
+	if o.broadcast != nil {
+		o.broadcast.Frame(o.match.WorldInputs(inputs))
+	}
+}
+
+func (o *onlineGame) updateRollback(local func() simulation.Input) {
+	var in simulation.Input
+	if o.rollback.NeedsInput() {
+		in = local()
+	}
+	o.rollback.Update(in)
+	o.stalled = o.rollback.Stalled()
+}
+
+func (g *Game) updateOnline() {
+	if g.actions.IsJustPressed(input.Back) {
+		g.reset()
+		return
+	}
+	var online = g.online
+	if online.session.Err() != nil {
+		return
+	}
+
+	if g.actions.IsJustPressed(input.Fire) || g.isFireTapped() {
+		online.pendingFire = true
+	}
+	if online.rollback != nil {
+		online.updateRollback(g.localOnlineInput)
+	} else {
+		online.updateLockstep(g.localOnlineInput)
+	}
+	if online.match.IsOver() {
+		g.screen = Screen.GameOver
+	}
+}
+
+func (g *Game) rivalScore() int {
+	return g.online.match.World(g.online.session.Remote()).Score
+}
+
+func (g *Game) DrawOnlineStatus(screen *ebiten.Image, palette theme.Palette) {
+	var face = &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   normalFontSize,
+	}
+	if g.online.session.Settings.Mode == GameMode.Versus {
+		textOp := &text.DrawOptions{}
+		textOp.GeoM.Translate(250, 13)
+		textOp.ColorScale.ScaleWithColor(palette.Text)
+		text.Draw(screen, "RIVAL "+strconv.Itoa(g.rivalScore()), face, textOp)
+	}
+
+	var msg string
+	if err := g.online.session.Err(); errors.Is(err, netplay.ErrDesync) {
+		msg = "DESYNC DETECTED"
+	} else if err != nil {
+		msg = "CONNECTION LOST"
+	} else if g.online.stalled > stallWarningTicks {
+		msg = "WAITING FOR OTHER PLAYER"
+	} else if !g.world.CanPlay() {
+		msg = "WAITING FOR RIVAL"
+	}
+	if msg != "" {
+		textOp := &text.DrawOptions{}
+		textOp.GeoM.Translate(float64(logicalWidth/2), float64(logicalHeight/2))
+		textOp.ColorScale.ScaleWithColor(palette.Accent)
+		textOp.PrimaryAlign = text.AlignCenter
+		text.Draw(screen, msg, face, textOp)
+	}
+}
+
+func (g *Game) onlineMenuValue(item int) string {
+	switch item {
+	case addressOnlineItem:
+		if g.onlineMenu.editing {
+			return g.onlineMenu.address + "_"
+		}
+		return g.onlineMenu.address
+	case inputDelayOnlineItem:
+		return fmt.Sprintf("< %d >", g.onlineMenu.inputDelay)
+	case modeOnlineItem:
+		return "< " + gameModeNames[g.onlineMenu.mode] + " >"
+	case netcodeOnlineItem:
+		if g.onlineMenu.rollback {
+			return "< ROLLBACK >"
+		}
+		return "< LOCKSTEP >"
+	}
+	return ""
+}
+
+func (g *Game) DrawOnlineMenu(screen *ebiten.Image, palette theme.Palette) {
+	face := &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   bigFontSize,
+	}
+	textOp := &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), 80)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	textOp.PrimaryAlign = text.AlignCenter
+	textOp.SecondaryAlign = text.AlignCenter
+	text.Draw(screen, "ONLINE", face, textOp)
+
+	face = &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   normalFontSize,
+	}
+	for i, item := range onlineMenuItems {
+		var label = item
+		if g.onlineMenu.selection == i {
+			label = "->" + label
+		}
+		var y = 150 + float64(25*i)
+
+		textOp = &text.DrawOptions{}
+		textOp.GeoM.Translate(100, y)
+		textOp.ColorScale.ScaleWithColor(palette.Accent)
+		text.Draw(screen, label, face, textOp)
+
+		textOp = &text.DrawOptions{}
+		textOp.GeoM.Translate(340, y)
+		textOp.ColorScale.ScaleWithColor(palette.Text)
+		text.Draw(screen, g.onlineMenuValue(i), face, textOp)
+	}
+
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), 300)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	textOp.PrimaryAlign = text.AlignCenter
+	text.Draw(screen, g.onlineMenu.status, face, textOp)
+
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), logicalHeight-40)
+	textOp.ColorScale.ScaleWithColor(palette.Text)
+	textOp.PrimaryAlign = text.AlignCenter
+	text.Draw(screen, "PRESS "+g.keyName(input.Back)+" TO GO BACK", face, textOp)
+}
@@ -0,0 +1,43 @@
+package particles
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	whiteImage = ebiten.NewImage(3, 3)
+	// Only the middle pixel is sampled, so the squares have clean edges.
+	whiteSubImage = whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+)
+
+func init() {
+	whiteImage.Fill(color.White)
+}
+
+// Draw draws every particle as a square in one call to DrawTriangles.
+func (s *System) Draw(screen *ebiten.Image) {
+	if s.particles.Len() == 0 {
+		return
+	}
+	s.vertices = s.vertices[:0]
+	s.indices = s.indices[:0]
+	for _, p := range s.particles.Values() {
+		var c = p.Color()
+		var r, g, b, a = float32(c.R) / 0xff, float32(c.G) / 0xff, float32(c.B) / 0xff, float32(c.A) / 0xff
+		var x0, y0 = float32(p.X - p.Size/2), float32(p.Y - p.Size/2)
+		var x1, y1 = x0 + float32(p.Size), y0 + float32(p.Size)
+		var first = uint16(len(s.vertices))
+		for _, corner := range [4][2]float32{{x0, y0}, {x1, y0}, {x0, y1}, {x1, y1}} {
+			s.vertices = append(s.vertices, ebiten.Vertex{
+				DstX: corner[0], DstY: corner[1],
+				SrcX: 1, SrcY: 1,
+				ColorR: r, ColorG: g, ColorB: b, ColorA: a,
+			})
+		}
+		s.indices = append(s.indices, first, first+1, first+2, first+1, first+3, first+2)
+	}
+	screen.DrawTriangles(s.vertices, s.indices, whiteSubImage, &ebiten.DrawTrianglesOptions{})
+}
@@ -0,0 +1,134 @@
+// Package particles animates short lived effects like explosions, sparks and
+// debris. Particles are only for show, so they live outside the simulation
+// and use their own random numbers.
+package particles
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/akshayxml/spaders/ecs"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// MaxParticles is how many particles can be alive at once. Bursts beyond it
+// are cut short.
+const MaxParticles = 4096
+
+type Particle struct {
+	X, Y     float64
+	VX, VY   float64
+	Gravity  float64
+	Size     float64
+	Age      int
+	Lifetime int
+	From, To color.RGBA
+}
+
+// Emitter describes a burst of particles. Each one flies off at a random
+// angle up to Spread radians either side of Angle, where 0 is to the right
+// and π/2 is down, at between MinSpeed and MaxSpeed pixels a tick. Colors
+// fade from From when a particle is born to To when it dies.
+type Emitter struct {
+	Count              int
+	Angle, Spread      float64
+	MinSpeed, MaxSpeed float64
+	// Lifetime in ticks, with up to LifetimeJitter ticks more or less.
+	Lifetime, LifetimeJitter int
+	// Added to the vertical speed every tick.
+	Gravity  float64
+	Size     float64
+	From, To color.RGBA
+}
+
+// System is a pool of particles drawn together in a single batch. Each
+// particle is an entity of its own registry, apart from the simulation's.
+type System struct {
+	entities  *ecs.Registry
+	particles *ecs.Store[Particle]
+	rng       *rand.Rand
+	vertices  []ebiten.Vertex
+	indices   []uint16
+}
+
+// systems are the parts of Update in the order they run.
+var systems = ecs.Systems[*System]{
+	(*System).age,
+	(*System).move,
+}
+
+func NewSystem(seed int64) *System {
+	var s = &System{rng: rand.New(rand.NewSource(seed))}
+	s.Clear()
+	return s
+}
+
+// Emit starts a burst of particles at x, y.
+func (s *System) Emit(e Emitter, x, y float64) {
+	for i := 0; i < e.Count && s.particles.Len() < MaxParticles; i++ {
+		var angle = e.Angle + (s.rng.Float64()*2-1)*e.Spread
+		var speed = e.MinSpeed + s.rng.Float64()*(e.MaxSpeed-e.MinSpeed)
+		var lifetime = e.Lifetime
+		if e.LifetimeJitter > 0 {
+			lifetime += s.rng.Intn(2*e.LifetimeJitter+1) - e.LifetimeJitter
+		}
+		s.particles.Add(s.entities.Create(), Particle{
+			X:        x,
+			Y:        y,
+			VX:       math.Cos(angle) * speed,
+			VY:       math.Sin(angle) * speed,
+			Gravity:  e.Gravity,
+			Size:     e.Size,
+			Lifetime: max(lifetime, 1),
+			From:     e.From,
+			To:       e.To,
+		})
+	}
+}
+
+// Update moves every particle on by a tick and drops the ones that have
+// lived out their lifetime.
+func (s *System) Update() {
+	systems.Run(s)
+}
+
+func (s *System) age() {
+	for i := 0; i < s.particles.Len(); {
+		var p = s.particles.At(i)
+		p.Age++
+		if p.Age >= p.Lifetime {
+			s.entities.Destroy(s.particles.Entity(i))
+			continue
+		}
+		i++
+	}
+}
+
+func (s *System) move() {
+	for i := 0; i < s.particles.Len(); i++ {
+		var p = s.particles.At(i)
+		p.VY += p.Gravity
+		p.X += p.VX
+		p.Y += p.VY
+	}
+}
+
+// Clear drops every particle by starting over with an empty registry.
+func (s *System) Clear() {
+	s.entities = ecs.NewRegistry()
+	s.particles = ecs.NewStore[Particle](s.entities)
+}
+
+func (s *System) Len() int {
+	return s.particles.Len()
+}
+
+// Color is the color of the particle at its current age.
+func (p Particle) Color() color.RGBA {
+	var t = float64(p.Age) / float64(p.Lifetime)
+	var lerp = func(from, to uint8) uint8 {
+		return uint8(math.Round(float64(from) + (float64(to)-float64(from))*t))
+	}
+	return color.RGBA{lerp(p.From.R, p.To.R), lerp(p.From.G, p.To.G), lerp(p.From.B, p.To.B), lerp(p.From.A, p.To.A)}
+}
@@ -0,0 +1,77 @@
+package render
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/akshayxml/spaders/models/EnemyType"
+	"github.com/akshayxml/spaders/models/EntityState"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/sprites"
+	"github.com/akshayxml/spaders/theme"
+)
+
+// Canvas is something the world can be drawn on: the game's screen, or an
+// image for a recording. DrawHUD and DrawWorld are shared by both, so the
+// two always look the same.
+type Canvas interface {
+	Rect(x, y, width, height float64, c color.RGBA)
+	// Text draws s in the game's font with x, y at the top left corner of
+	// the line.
+	Text(s string, x, y float64, c color.RGBA)
+	Enemy(t EnemyType.EnemyType, x, y, scale float64, tint color.RGBA)
+}
+
+// DrawHUD draws the score and lives along the top of the screen.
+func DrawHUD(c Canvas, w *simulation.World, p theme.Palette) {
+	c.Text("SCORE", 50, 13, p.Text)
+	c.Text(strconv.Itoa(w.Score), 150, 13, p.Accent)
+	c.Text("LIVES", 400, 13, p.Text)
+	if len(w.Players) > 1 {
+		var lives = []string{}
+		for i, player := range w.Players {
+			lives = append(lives, "P"+strconv.Itoa(i+1)+" "+strconv.Itoa(player.Lives))
+		}
+		c.Text(strings.Join(lives, " "), 480, 13, p.Accent)
+		return
+	}
+	for i := 0; i < w.Players[0].Lives && i < 3; i++ {
+		for _, rect := range sprites.GetPlayerRectangles() {
+			c.Rect(rect.Position.X+480+float64(50*i), rect.Position.Y+10, rect.Width, rect.Height, p.Player)
+		}
+	}
+}
+
+// DrawWorld draws the bullets, bunkers, invaders, cannons and the ground.
+func DrawWorld(c Canvas, w *simulation.World, p theme.Palette) {
+	for _, player := range w.Players {
+		if player.Bullet.IsActive {
+			for _, rect := range sprites.GetPlayerBulletRectangles() {
+				c.Rect(rect.Position.X+player.Bullet.Position.X, rect.Position.Y+player.Bullet.Position.Y, rect.Width, rect.Height, p.PlayerBullet)
+			}
+		}
+	}
+	for _, bullet := range w.EnemyBullets.Values() {
+		for _, rect := range sprites.GetEnemyBulletRectangles() {
+			c.Rect(rect.Position.X+bullet.Position.X, rect.Position.Y+bullet.Position.Y, rect.Width, rect.Height, p.EnemyBullet)
+		}
+	}
+	for _, sprite := range w.BunkerSprites {
+		c.Rect(sprite.Position.X, sprite.Position.Y, sprite.Width, sprite.Height, p.Bunker)
+	}
+	for _, enemy := range w.Enemies {
+		if enemy.State == EntityState.Alive {
+			c.Enemy(enemy.Type, enemy.Position.X, enemy.Position.Y, enemy.Scale, p.Enemy)
+		}
+	}
+	for _, player := range w.Players {
+		if player.Lives == 0 {
+			continue
+		}
+		for _, rect := range sprites.GetPlayerRectangles() {
+			c.Rect(rect.Position.X+player.Position.X, rect.Position.Y+player.Position.Y, rect.Width, rect.Height, p.Player)
+		}
+	}
+	c.Rect(simulation.LeftBoundary, simulation.Height-11, simulation.Width-50-simulation.LeftBoundary, 2, p.Ground)
+}
@@ -0,0 +1,74 @@
+package render
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/akshayxml/spaders/models/EnemyType"
+	"github.com/akshayxml/spaders/models/EntityState"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/sprites"
+	"github.com/akshayxml/spaders/theme"
+)
+
+// recorder is a Canvas that remembers what was drawn on it.
+type recorder struct {
+	rects   map[color.RGBA]int
+	texts   []string
+	enemies int
+}
+
+func (r *recorder) Rect(x, y, width, height float64, c color.RGBA) {
+	r.rects[c]++
+}
+
+func (r *recorder) Text(s string, x, y float64, c color.RGBA) {
+	r.texts = append(r.texts, s)
+}
+
+func (r *recorder) Enemy(t EnemyType.EnemyType, x, y, scale float64, tint color.RGBA) {
+	r.enemies++
+}
+
+func TestDrawWorld(t *testing.T) {
+	// Every part in a color of its own, to tell them apart.
+	var p = theme.Palette{
+		Player:       color.RGBA{1, 0, 0, 0xFF},
+		Bunker:       color.RGBA{2, 0, 0, 0xFF},
+		PlayerBullet: color.RGBA{3, 0, 0, 0xFF},
+		EnemyBullet:  color.RGBA{4, 0, 0, 0xFF},
+		Ground:       color.RGBA{5, 0, 0, 0xFF},
+	}
+	var w = simulation.New(1, 1, 2)
+	w.Enemies[0].State = EntityState.Dead
+	w.Players[1].Lives = 0
+	w.Players[0].Bullet.IsActive = true
+
+	var c = &recorder{rects: map[color.RGBA]int{}}
+	DrawWorld(c, w, p)
+	if c.enemies != len(w.Enemies)-1 {
+		t.Errorf("drew %d invaders, want %d", c.enemies, len(w.Enemies)-1)
+	}
+	if got, want := c.rects[p.Player], len(sprites.GetPlayerRectangles()); got != want {
+		t.Errorf("drew %d rectangles of cannon, want %d for the one with lives left", got, want)
+	}
+	if got, want := c.rects[p.PlayerBullet], len(sprites.GetPlayerBulletRectangles()); got != want {
+		t.Errorf("drew %d rectangles of bullet, want %d", got, want)
+	}
+	if c.rects[p.Bunker] != len(w.BunkerSprites) || c.rects[p.Ground] != 1 {
+		t.Errorf("drew %d bunker pieces and %d grounds, want %d and 1", c.rects[p.Bunker], c.rects[p.Ground], len(w.BunkerSprites))
+	}
+
+	c = &recorder{rects: map[color.RGBA]int{}}
+	DrawHUD(c, w, p)
+	var want = []string{"SCORE", "0", "LIVES", "P1 3 P2 0"}
+	if len(c.texts) != len(want) {
+		t.Fatalf("HUD reads %q, want %q", c.texts, want)
+	}
+	for i := range want {
+		if c.texts[i] != want[i] {
+			t.Errorf("HUD reads %q, want %q", c.texts, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package render
+
+import (
+	"bufio"
+	"compress/lzw"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"io"
+
+	"github.com/akshayxml/spaders/theme"
+)
+
+// Palette is the 256 colors GIFs are saved with: every color of the theme,
+// so that they come out exactly, filled up with the web safe colors for the
+// background and the edges of the text.
+func Palette(p theme.Palette) color.Palette {
+	var colors = []color.RGBA{{0, 0, 0, 0xFF}, {0xFF, 0xFF, 0xFF, 0xFF}, p.Accent, p.Text, p.Player, p.Bunker, p.PlayerBullet, p.EnemyBullet, p.Enemy, p.Ground}
+	for _, gel := range p.Gels {
+		colors = append(colors, gel.Color)
+	}
+	for _, c := range palette.WebSafe {
+		colors = append(colors, color.RGBAModel.Convert(c).(color.RGBA))
+	}
+	var seen = map[color.RGBA]bool{}
+	var result = color.Palette{}
+	for _, c := range colors {
+		if !seen[c] && len(result) < 256 {
+			seen[c] = true
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// GIFWriter writes an animated GIF one frame at a time, so that long
+// replays don't have to be held in memory. image/gif can only encode a
+// whole animation at once.
+type GIFWriter struct {
+	w       *bufio.Writer
+	width   int
+	height  int
+	palette color.Palette
+	// The palette index of every color seen so far. Frames have few colors,
+	// so this is much faster than searching the palette for every pixel.
+	indexes map[color.RGBA]uint8
+	// The palette indexes of the last frame and of the one being written.
+	// Only the box around the pixels that changed is written, over the last
+	// frame.
+	previous, pixels []byte
+	frames           int
+	region           []byte
+	block            blockWriter
+}
+
+// NewGIFWriter writes the header of a GIF that loops forever. The palette
+// must have 256 colors at most.
+func NewGIFWriter(w io.Writer, width, height int, p color.Palette) (*GIFWriter, error) {
+	if len(p) == 0 || len(p) > 256 {
+		return nil, errors.New("render: a GIF palette needs 1 to 256 colors")
+	}
+	var g = &GIFWriter{
+		w:        bufio.NewWriter(w),
+		width:    width,
+		height:   height,
+		palette:  p,
+		indexes:  map[color.RGBA]uint8{},
+		previous: make([]byte, width*height),
+		pixels:   make([]byte, width*height),
+	}
+	g.block.w = g.w
+
+	g.w.WriteString("GIF89a")
+	g.uint16(width)
+	g.uint16(height)
+	// A global color table of 256 entries, with 8 bits per primary color.
+	g.w.Write([]byte{0xF7, 0, 0})
+	for i := 0; i < 256; i++ {
+		var r, gr, b uint8
+		if i < len(p) {
+			var c = color.RGBAModel.Convert(p[i]).(color.RGBA)
+			r, gr, b = c.R, c.G, c.B
+		}
+		g.w.Write([]byte{r, gr, b})
+	}
+	// The NETSCAPE2.0 extension makes it loop forever.
+	g.w.Write([]byte{0x21, 0xFF, 0x0B})
+	g.w.WriteString("NETSCAPE2.0")
+	g.w.Write([]byte{0x03, 0x01, 0, 0, 0})
+	return g, g.w.Flush()
+}
+
+func (g *GIFWriter) uint16(v int) {
+	g.w.Write(binary.LittleEndian.AppendUint16(nil, uint16(v)))
+}
+
+func (g *GIFWriter) index(c color.RGBA) uint8 {
+	if i, ok := g.indexes[c]; ok {
+		return i
+	}
+	var i = uint8(g.palette.Index(c))
+	g.indexes[c] = i
+	return i
+}
+
+// WriteFrame adds img, shown for delay hundredths of a second.
+func (g *GIFWriter) WriteFrame(img *image.RGBA, delay int) error {
+	if img.Bounds().Dx() != g.width || img.Bounds().Dy() != g.height {
+		return errors.New("render: GIF frame is the wrong size")
+	}
+	var last, lastIndex = color.RGBA{}, -1
+	for y := 0; y < g.height; y++ {
+		var row = img.Pix[y*img.Stride:]
+		for x := 0; x < g.width; x++ {
+			var c = color.RGBA{row[4*x], row[4*x+1], row[4*x+2], 0xFF}
+			if c != last || lastIndex < 0 {
+				last, lastIndex = c, int(g.index(c))
+			}
+			g.pixels[y*g.width+x] = uint8(lastIndex)
+		}
+	}
+	var changed = image.Rect(0, 0, g.width, g.height)
+	if g.frames > 0 {
+		changed = g.changed()
+	}
+	g.region = g.region[:0]
+	for y := changed.Min.Y; y < changed.Max.Y; y++ {
+		g.region = append(g.region, g.pixels[y*g.width+changed.Min.X:y*g.width+changed.Max.X]...)
+	}
+	g.previous, g.pixels = g.pixels, g.previous
+	g.frames++
+
+	// Graphic control extension with the delay, leaving the frame in place
+	// for the next one to be drawn over.
+	g.w.Write([]byte{0x21, 0xF9, 0x04, 0x04})
+	g.uint16(delay)
+	g.w.Write([]byte{0, 0})
+	// Image descriptor using the global colors.
+	g.w.WriteByte(0x2C)
+	g.uint16(changed.Min.X)
+	g.uint16(changed.Min.Y)
+	g.uint16(changed.Dx())
+	g.uint16(changed.Dy())
+	g.w.WriteByte(0)
+
+	g.w.WriteByte(8)
+	var lzwWriter = lzw.NewWriter(&g.block, lzw.LSB, 8)
+	if _, err := lzwWriter.Write(g.region); err != nil {
+		return err
+	}
+	if err := lzwWriter.Close(); err != nil {
+		return err
+	}
+	g.block.flush()
+	g.w.WriteByte(0)
+	return g.w.Flush()
+}
+
+// changed returns the box around the pixels that differ from the last
+// frame, or a single pixel when none do, since a frame can't be empty.
+func (g *GIFWriter) changed() image.Rectangle {
+	var box = image.Rectangle{Min: image.Pt(g.width, g.height)}
+	for y := 0; y < g.height; y++ {
+		var row, prev = g.pixels[y*g.width : (y+1)*g.width], g.previous[y*g.width : (y+1)*g.width]
+		for x := range row {
+			if row[x] != prev[x] {
+				box.Min.X, box.Max.X = min(box.Min.X, x), max(box.Max.X, x+1)
+				box.Min.Y, box.Max.Y = min(box.Min.Y, y), max(box.Max.Y, y+1)
+			}
+		}
+	}
+	if box.Empty() {
+		return image.Rect(0, 0, 1, 1)
+	}
+	return box
+}
+
+// Close writes the end of the GIF. It doesn't close the underlying writer.
+func (g *GIFWriter) Close() error {
+	g.w.WriteByte(0x3B)
+	return g.w.Flush()
+}
+
+// blockWriter splits the compressed pixels into the sub-blocks of up to 255
+// bytes that GIF wants.
+type blockWriter struct {
+	w   *bufio.Writer
+	buf [255]byte
+	n   int
+}
+
+func (b *blockWriter) Write(p []byte) (int, error) {
+	var written = len(p)
+	for len(p) > 0 {
+		var n = copy(b.buf[b.n:], p)
+		b.n += n
+		p = p[n:]
+		if b.n == len(b.buf) {
+			b.flush()
+		}
+	}
+	return written, nil
+}
+
+func (b *blockWriter) flush() {
+	if b.n == 0 {
+		return
+	}
+	b.w.WriteByte(byte(b.n))
+	b.w.Write(b.buf[:b.n])
+	b.n = 0
+}
@@ -0,0 +1,83 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"testing"
+
+	"github.com/akshayxml/spaders/theme"
+)
+
+func TestGIFRoundTrip(t *testing.T) {
+	const width, height = 40, 30
+	var p = Palette(theme.Neon)
+	var frame = func(x int, c color.RGBA) *image.RGBA {
+		var img = image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(img, img.Bounds(), image.Black, image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(x, 5, x+6, 12), image.NewUniform(c), image.Point{}, draw.Src)
+		return img
+	}
+	var frames = []*image.RGBA{
+		frame(2, theme.Neon.Player),
+		frame(10, theme.Neon.Enemy),
+		// Nothing changes, which still has to make a frame.
+		frame(10, theme.Neon.Enemy),
+		frame(30, theme.Neon.Accent),
+	}
+	var delays = []int{5, 5, 10, 20}
+
+	var buf bytes.Buffer
+	writer, err := NewGIFWriter(&buf, width, height, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, img := range frames {
+		if err := writer.WriteFrame(img, delays[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Config.Width != width || decoded.Config.Height != height {
+		t.Errorf("GIF is %dx%d, want %dx%d", decoded.Config.Width, decoded.Config.Height, width, height)
+	}
+	if len(decoded.Image) != len(frames) {
+		t.Fatalf("GIF has %d frames, want %d", len(decoded.Image), len(frames))
+	}
+	if decoded.LoopCount != 0 {
+		t.Errorf("loop count %d, want 0 to loop forever", decoded.LoopCount)
+	}
+	// Frames only hold the box that changed, drawn over the last frame.
+	var screen = image.NewPaletted(image.Rect(0, 0, width, height), p)
+	for i, img := range decoded.Image {
+		if decoded.Delay[i] != delays[i] {
+			t.Errorf("frame %d lasts %d, want %d", i, decoded.Delay[i], delays[i])
+		}
+		if decoded.Disposal[i] != gif.DisposalNone {
+			t.Errorf("frame %d is disposed of with %d, want it left in place", i, decoded.Disposal[i])
+		}
+		var b = img.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				screen.SetColorIndex(x, y, img.ColorIndexAt(x, y))
+			}
+		}
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				var want = uint8(p.Index(frames[i].RGBAAt(x, y)))
+				if got := screen.ColorIndexAt(x, y); got != want {
+					t.Fatalf("frame %d has index %d at %d,%d, want %d", i, got, x, y, want)
+				}
+			}
+		}
+	}
+}
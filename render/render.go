@@ -0,0 +1,173 @@
+// Package render draws the world into plain images on the CPU, without a
+// window or a GPU, the way the game draws it on screen. It is used for the
+// recordings the game saves and to turn replays into GIFs. The game draws
+// its screen with DrawHUD and DrawWorld too.
+//
+// Particles and the CRT effect are left out.
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"os"
+
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/akshayxml/spaders/models/EnemyType"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/theme"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	Width    = int(simulation.Width)
+	Height   = int(simulation.Height)
+	fontSize = 18
+)
+
+// Assets are the pictures and font the game is drawn with.
+type Assets struct {
+	Background image.Image
+	Enemies    map[EnemyType.EnemyType]image.Image
+	Font       *opentype.Font
+}
+
+func decodeImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+// LoadAssets reads the background, the invader pictures and the font from
+// the files at those paths.
+func LoadAssets(background string, enemies map[EnemyType.EnemyType]string, fontPath string) (*Assets, error) {
+	var assets = &Assets{Enemies: map[EnemyType.EnemyType]image.Image{}}
+	var err error
+	assets.Background, err = decodeImage(background)
+	if err != nil {
+		return nil, err
+	}
+	for t, path := range enemies {
+		assets.Enemies[t], err = decodeImage(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	data, err := os.ReadFile(fontPath)
+	if err != nil {
+		return nil, err
+	}
+	assets.Font, err = opentype.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return assets, nil
+}
+
+type enemyKey struct {
+	t     EnemyType.EnemyType
+	scale float64
+	tint  color.RGBA
+}
+
+// Renderer draws worlds with one palette.
+type Renderer struct {
+	assets     *Assets
+	palette    theme.Palette
+	face       font.Face
+	background *image.RGBA
+	// Invader pictures scaled and tinted, made the first time they are
+	// needed.
+	enemies map[enemyKey]*image.RGBA
+}
+
+func New(assets *Assets, palette theme.Palette) (*Renderer, error) {
+	face, err := opentype.NewFace(assets.Font, &opentype.FaceOptions{Size: fontSize, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	var r = &Renderer{assets: assets, palette: palette, face: face, enemies: map[enemyKey]*image.RGBA{}}
+	r.background = image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(r.background, r.background.Bounds(), image.Black, image.Point{}, draw.Src)
+	if palette.Background {
+		// The game draws the background at half its size.
+		var b = assets.Background.Bounds()
+		xdraw.ApproxBiLinear.Scale(r.background, image.Rect(0, 0, b.Dx()/2, b.Dy()/2), assets.Background, b, draw.Src, nil)
+	}
+	return r, nil
+}
+
+// imageCanvas draws on dst with r's pictures and font.
+type imageCanvas struct {
+	r   *Renderer
+	dst *image.RGBA
+}
+
+func (c imageCanvas) Rect(x, y, width, height float64, clr color.RGBA) {
+	var rect = image.Rect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+width)), int(math.Round(y+height)))
+	draw.Draw(c.dst, rect, image.NewUniform(clr), image.Point{}, draw.Over)
+}
+
+func (c imageCanvas) Text(s string, x, y float64, clr color.RGBA) {
+	var d = font.Drawer{Dst: c.dst, Src: image.NewUniform(clr), Face: c.r.face}
+	d.Dot = fixed.Point26_6{X: fixed.I(int(x)), Y: fixed.I(int(y)) + c.r.face.Metrics().Ascent}
+	d.DrawString(s)
+}
+
+func (c imageCanvas) Enemy(t EnemyType.EnemyType, x, y, scale float64, tint color.RGBA) {
+	var img = c.r.enemy(enemyKey{t, scale, tint})
+	var at = image.Pt(int(math.Round(x)), int(math.Round(y)))
+	draw.Draw(c.dst, img.Bounds().Add(at), img, image.Point{}, draw.Over)
+}
+
+func (r *Renderer) enemy(key enemyKey) *image.RGBA {
+	if img, ok := r.enemies[key]; ok {
+		return img
+	}
+	var src = r.assets.Enemies[key.t]
+	var b = src.Bounds()
+	var img = image.NewRGBA(image.Rect(0, 0, int(math.Ceil(float64(b.Dx())*key.scale)), int(math.Ceil(float64(b.Dy())*key.scale))))
+	xdraw.NearestNeighbor.Scale(img, img.Bounds(), src, b, draw.Src, nil)
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i] = uint8(uint16(img.Pix[i]) * uint16(key.tint.R) / 0xFF)
+		img.Pix[i+1] = uint8(uint16(img.Pix[i+1]) * uint16(key.tint.G) / 0xFF)
+		img.Pix[i+2] = uint8(uint16(img.Pix[i+2]) * uint16(key.tint.B) / 0xFF)
+	}
+	r.enemies[key] = img
+	return img
+}
+
+// Draw draws w into dst, which must be the size of the screen, with the
+// score and lives along the top like during a game.
+func (r *Renderer) Draw(dst *image.RGBA, w *simulation.World) {
+	copy(dst.Pix, r.background.Pix)
+	var c = imageCanvas{r, dst}
+	DrawHUD(c, w, r.palette)
+	DrawWorld(c, w, r.palette)
+	r.gels(dst)
+}
+
+// gels multiplies the strips of the palette's gels by their colors.
+func (r *Renderer) gels(dst *image.RGBA) {
+	for _, gel := range r.palette.Gels {
+		var top, bottom = max(int(gel.Top), 0), min(int(math.Ceil(gel.Bottom)), dst.Bounds().Dy())
+		for y := top; y < bottom; y++ {
+			var row = dst.Pix[y*dst.Stride : y*dst.Stride+4*dst.Bounds().Dx()]
+			for i := 0; i < len(row); i += 4 {
+				row[i] = uint8(uint16(row[i]) * uint16(gel.Color.R) / 0xFF)
+				row[i+1] = uint8(uint16(row[i+1]) * uint16(gel.Color.G) / 0xFF)
+				row[i+2] = uint8(uint16(row[i+2]) * uint16(gel.Color.B) / 0xFF)
+			}
+		}
+	}
+}
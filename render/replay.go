@@ -0,0 +1,57 @@
+package render
+
+import (
+	"image"
+	"io"
+
+	"github.com/akshayxml/spaders/simulation"
+)
+
+// FrameTicks is how many ticks each frame of a GIF lasts. At 60 ticks a
+// second that makes 20 frames a second, whose delay of 5 hundredths of a
+// second GIF can hold exactly.
+const FrameTicks = 3
+
+var frameDelay = FrameTicks * 100 / simulation.TicksPerSecond
+
+// WorldsGIF writes a GIF with one frame for each world.
+func WorldsGIF(w io.Writer, worlds []*simulation.World, r *Renderer) error {
+	gif, err := NewGIFWriter(w, Width, Height, Palette(r.palette))
+	if err != nil {
+		return err
+	}
+	var frame = image.NewRGBA(image.Rect(0, 0, Width, Height))
+	for _, world := range worlds {
+		r.Draw(frame, world)
+		if err := gif.WriteFrame(frame, frameDelay); err != nil {
+			return err
+		}
+	}
+	return gif.Close()
+}
+
+// ReplayGIF plays replay and writes a GIF of it, showing whichever world
+// stepped, like the screen does in alternating games.
+func ReplayGIF(w io.Writer, replay *simulation.Replay, r *Renderer) error {
+	gif, err := NewGIFWriter(w, Width, Height, Palette(r.palette))
+	if err != nil {
+		return err
+	}
+	var frame = image.NewRGBA(image.Rect(0, 0, Width, Height))
+	var ticks = 0
+	var writeErr error
+	_, err = replay.Watch(func(world *simulation.World) {
+		if ticks%FrameTicks == 0 && writeErr == nil {
+			r.Draw(frame, world)
+			writeErr = gif.WriteFrame(frame, frameDelay)
+		}
+		ticks++
+	})
+	if err != nil {
+		return err
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+	return gif.Close()
+}
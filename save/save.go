@@ -0,0 +1,164 @@
+package save
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/akshayxml/spaders/models/GameMode"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/snapshot"
+)
+
+const (
+	Version  = 3
+	fileName = "save.json"
+)
+
+var ErrNoSave = errors.New("save: no saved run")
+
+var schema = snapshot.New("save", Version, func() any { return &Run{} }).
+	Migrate(1, dropBunkerColors).
+	Migrate(2, renameAutopilot)
+
+func dropBunkerColors(doc map[string]any) error {
+	for _, world := range snapshot.Objects(doc["worlds"]) {
+		for _, sprite := range snapshot.Objects(world["bunkerSprites"]) {
+			delete(sprite, "color")
+		}
+	}
+	return nil
+}
+
+// renameAutopilot follows the flag to its new name, since it was set by the
+// debug controls as well as the autopilot.
+func renameAutopilot(doc map[string]any) error {
+	if assisted, ok := doc["autopilot"]; ok {
+		doc["assisted"] = assisted
+		delete(doc, "autopilot")
+	}
+	return nil
+}
+
+// Run is a local game that was quit before it was over. Alternating games
+// have a world per player; the replay lets a finished run still be
+// submitted to the leaderboard unless the autopilot played part of it.
+type Run struct {
+	Mode        GameMode.GameMode  `json:"mode"`
+	Difficulty  int                `json:"difficulty"`
+	CurrentTurn int                `json:"currentTurn"`
+	Worlds      []World            `json:"worlds"`
+	Replay      *simulation.Replay `json:"replay"`
+	// Whether the autopilot or the debug controls were used, which keeps
+	// the run off the leaderboard.
+	Assisted bool `json:"assisted,omitempty"`
+}
+
+// Path is next to the config file.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "spaders", fileName), nil
+}
+
+func Exists() bool {
+	path, err := Path()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// NewWorlds creates the worlds of the run in the state they were saved in.
+func (run *Run) NewWorlds() []*simulation.World {
+	var worlds = make([]*simulation.World, len(run.Worlds))
+	for i, saved := range run.Worlds {
+		worlds[i] = saved.NewWorld()
+		worlds[i].TakesTurns = run.Mode == GameMode.Alternating
+	}
+	return worlds
+}
+
+func Write(run *Run) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := schema.Encode(run)
+	if err != nil {
+		return err
+	}
+	var tmp = path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func Read() (*Run, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNoSave
+	}
+	if err != nil {
+		return nil, err
+	}
+	var run Run
+	if err := schema.Decode(data, &run); err != nil {
+		return nil, err
+	}
+	if err := run.validate(); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// validate checks that the run can be continued, since a hand edited or
+// damaged file would otherwise crash the game once it is.
+func (run *Run) validate() error {
+	var turns, players = 1, 1
+	switch run.Mode {
+	case GameMode.Single:
+	case GameMode.Alternating:
+		turns = 2
+	case GameMode.Coop:
+		players = 2
+	default:
+		return fmt.Errorf("save: mode %d can't be continued", run.Mode)
+	}
+	if run.Difficulty < 1 || run.Difficulty > 3 {
+		return fmt.Errorf("save: difficulty %d is not between 1 and 3", run.Difficulty)
+	}
+	if len(run.Worlds) != turns || run.CurrentTurn < 0 || run.CurrentTurn >= len(run.Worlds) {
+		return errors.New("save: file has no world to continue")
+	}
+	for _, world := range run.Worlds {
+		if len(world.Players) != players {
+			return fmt.Errorf("save: world has %d players, want %d", len(world.Players), players)
+		}
+	}
+	return nil
+}
+
+func Delete() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package save
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/akshayxml/spaders/models/GameMode"
+	"github.com/akshayxml/spaders/simulation"
+)
+
+var update = flag.Bool("update", false, "write the golden file of the current version")
+
+func TestGolden(t *testing.T) {
+	if *update {
+		if err := schema.UpdateGolden("../testdata/snapshots"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := schema.CheckGolden("../testdata/snapshots"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRenameAutopilot(t *testing.T) {
+	var data = []byte(`{"kind":"save","version":2,"data":{"mode":0,"difficulty":1,"autopilot":true}}`)
+	var run Run
+	if err := schema.Decode(data, &run); err != nil {
+		t.Fatal(err)
+	}
+	if !run.Assisted {
+		t.Error("a run the autopilot played is no longer marked as assisted")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var world = World{Players: []Player{{Lives: 3}}}
+	var tests = []struct {
+		name string
+		run  Run
+		ok   bool
+	}{
+		{"single", Run{Mode: GameMode.Single, Difficulty: 2, Worlds: []World{world}}, true},
+		{"alternating", Run{Mode: GameMode.Alternating, Difficulty: 1, CurrentTurn: 1, Worlds: []World{world, world}}, true},
+		{"difficulty too low", Run{Mode: GameMode.Single, Difficulty: 0, Worlds: []World{world}}, false},
+		{"difficulty too high", Run{Mode: GameMode.Single, Difficulty: 4, Worlds: []World{world}}, false},
+		{"versus", Run{Mode: GameMode.Versus, Difficulty: 1, Worlds: []World{world, world}}, false},
+		{"unknown mode", Run{Mode: 9, Difficulty: 1, Worlds: []World{world}}, false},
+		{"no worlds", Run{Mode: GameMode.Single, Difficulty: 1}, false},
+		{"turn out of range", Run{Mode: GameMode.Alternating, Difficulty: 1, CurrentTurn: 2, Worlds: []World{world, world}}, false},
+		{"coop with one player", Run{Mode: GameMode.Coop, Difficulty: 1, Worlds: []World{world}}, false},
+	}
+	for _, test := range tests {
+		if err := test.run.validate(); (err == nil) != test.ok {
+			t.Errorf("%s: validate() = %v, want ok %v", test.name, err, test.ok)
+		}
+	}
+}
+
+func TestResumeStepsLikeOriginal(t *testing.T) {
+	var fire = []simulation.Input{simulation.InputFire | simulation.InputLeft}
+	var world = simulation.New(7, 2, 1)
+	var resumed *simulation.World
+	for world.Tick < 1200 && world.CanPlay() {
+		if world.Tick == 600 {
+			resumed = FromWorld(world).NewWorld()
+		}
+		world.Step(fire)
+		if resumed != nil {
+			resumed.Step(fire)
+		}
+	}
+	if resumed == nil {
+		t.Fatal("the game ended before it was saved")
+	}
+	if resumed.Checksum() != world.Checksum() {
+		t.Errorf("resumed world checksum %x, want %x", resumed.Checksum(), world.Checksum())
+	}
+}
@@ -0,0 +1,161 @@
+package save
+
+import (
+	"github.com/akshayxml/spaders/models"
+	"github.com/akshayxml/spaders/models/EnemyType"
+	"github.com/akshayxml/spaders/models/EntityState"
+	"github.com/akshayxml/spaders/simulation"
+)
+
+// The types below are the save file's own copies of the game state. They
+// are kept separate from models so that changing a model does not silently
+// change the file format.
+
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type Bullet struct {
+	Position  Position `json:"position"`
+	Direction int      `json:"direction"`
+	Speed     int      `json:"speed"`
+	IsActive  bool     `json:"isActive"`
+	Height    float64  `json:"height"`
+}
+
+type Player struct {
+	Position Position `json:"position"`
+	Lives    int      `json:"lives"`
+	Speed    float64  `json:"speed"`
+	Bullet   Bullet   `json:"bullet"`
+}
+
+type Enemy struct {
+	Position Position                `json:"position"`
+	Type     EnemyType.EnemyType     `json:"type"`
+	Scale    float64                 `json:"scale"`
+	State    EntityState.EntityState `json:"state"`
+}
+
+type EnemyState struct {
+	EnemyCount          int      `json:"enemyCount"`
+	HorizontalDirection int      `json:"horizontalDirection"`
+	HorizontalSpeed     float64  `json:"horizontalSpeed"`
+	EnemyFireRate       int      `json:"enemyFireRate"`
+	EnemyBullets        []Bullet `json:"enemyBullets"`
+}
+
+// Bunker sprites are drawn in the bunker color, which is not part of the
+// game state and not saved since version 2.
+type Rectangle struct {
+	Position Position `json:"position"`
+	Width    float64  `json:"width"`
+	Height   float64  `json:"height"`
+}
+
+type World struct {
+	Players       []Player    `json:"players"`
+	Enemies       []Enemy     `json:"enemies"`
+	EnemyState    EnemyState  `json:"enemyState"`
+	BunkerSprites []Rectangle `json:"bunkerSprites"`
+	Score         int         `json:"score"`
+	Difficulty    int         `json:"difficulty"`
+	Tick          int64       `json:"tick"`
+	RandomState   uint64      `json:"randomState"`
+}
+
+func fromPosition(p models.Position) Position {
+	return Position{X: p.X, Y: p.Y}
+}
+
+func (p Position) model() models.Position {
+	return models.Position{X: p.X, Y: p.Y}
+}
+
+func fromBullet(b models.Bullet) Bullet {
+	return Bullet{Position: fromPosition(b.Position), Direction: b.Direction, Speed: b.Speed, IsActive: b.IsActive, Height: b.Height}
+}
+
+// model rebuilds Previous from Position. Step moves every bullet before it
+// checks collisions, so the old value is never read after a load.
+func (b Bullet) model() models.Bullet {
+	return models.Bullet{Position: b.Position.model(), Previous: b.Position.model(), Direction: b.Direction, Speed: b.Speed, IsActive: b.IsActive, Height: b.Height}
+}
+
+// FromWorld copies the state of w. Only the active enemy bullets are kept.
+func FromWorld(w *simulation.World) World {
+	var saved = World{
+		Score:       w.Score,
+		Difficulty:  w.Difficulty,
+		Tick:        w.Tick,
+		RandomState: w.RandomState(),
+		EnemyState: EnemyState{
+			EnemyCount:          w.EnemyState.EnemyCount,
+			HorizontalDirection: w.EnemyState.HorizontalDirection,
+			HorizontalSpeed:     w.EnemyState.HorizontalSpeed,
+			EnemyFireRate:       w.EnemyState.EnemyFireRate,
+			EnemyBullets:        []Bullet{},
+		},
+	}
+	for _, player := range w.Players {
+		saved.Players = append(saved.Players, Player{
+			Position: fromPosition(player.Position),
+			Lives:    player.Lives,
+			Speed:    player.Speed,
+			Bullet:   fromBullet(player.Bullet),
+		})
+	}
+	for _, enemy := range w.Enemies {
+		saved.Enemies = append(saved.Enemies, Enemy{Position: fromPosition(enemy.Position), Type: enemy.Type, Scale: enemy.Scale, State: enemy.State})
+	}
+	for _, bullet := range w.EnemyBullets.Values() {
+		saved.EnemyState.EnemyBullets = append(saved.EnemyState.EnemyBullets, fromBullet(bullet))
+	}
+	for _, sprite := range w.BunkerSprites {
+		saved.BunkerSprites = append(saved.BunkerSprites, Rectangle{
+			Position: fromPosition(sprite.Position),
+			Width:    sprite.Width,
+			Height:   sprite.Height,
+		})
+	}
+	return saved
+}
+
+// NewWorld creates a world in exactly the state that was saved.
+func (saved World) NewWorld() *simulation.World {
+	var w = simulation.New(0, saved.Difficulty, len(saved.Players))
+	w.Score = saved.Score
+	w.Tick = saved.Tick
+	w.SetRandomState(saved.RandomState)
+	for i, player := range saved.Players {
+		*w.Players[i] = models.Player{
+			Position: player.Position.model(),
+			Lives:    player.Lives,
+			Speed:    player.Speed,
+			Bullet:   player.Bullet.model(),
+		}
+	}
+	w.Enemies = w.Enemies[:0]
+	for _, enemy := range saved.Enemies {
+		w.Enemies = append(w.Enemies, models.Enemy{Position: enemy.Position.model(), Type: enemy.Type, Scale: enemy.Scale, State: enemy.State})
+	}
+	w.EnemyState = models.EnemyState{
+		EnemyCount:          saved.EnemyState.EnemyCount,
+		HorizontalDirection: saved.EnemyState.HorizontalDirection,
+		HorizontalSpeed:     saved.EnemyState.HorizontalSpeed,
+		EnemyFireRate:       saved.EnemyState.EnemyFireRate,
+	}
+	for _, bullet := range saved.EnemyState.EnemyBullets {
+		w.SpawnEnemyBullet(bullet.model())
+	}
+	w.BunkerSprites = w.BunkerSprites[:0]
+	for _, sprite := range saved.BunkerSprites {
+		w.BunkerSprites = append(w.BunkerSprites, models.Rectangle{
+			Position: sprite.Position.model(),
+			Width:    sprite.Width,
+			Height:   sprite.Height,
+		})
+	}
+	return w
+}
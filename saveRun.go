@@ -0,0 +1,69 @@
+package main
+
+import (
+	"log"
+
+	"github.com/akshayxml/spaders/models/GameMode"
+	"github.com/akshayxml/spaders/models/Screen"
+	"github.com/akshayxml/spaders/save"
+	"github.com/akshayxml/spaders/simulation"
+)
+
+// saveRun keeps a local game that is being quit so it can be continued from
+// the menu. Online games cannot be resumed alone and are not saved.
+func (g *Game) saveRun() {
+	if g.online != nil || g.spectate != nil {
+		return
+	}
+	if g.screen != Screen.Play && g.screen != Screen.Turn {
+		return
+	}
+	var run = &save.Run{
+		Mode:        g.mode,
+		Difficulty:  g.difficulty,
+		CurrentTurn: g.currentTurn,
+		Replay:      g.replay,
+		Assisted:    g.assisted,
+	}
+	for _, world := range g.turns {
+		run.Worlds = append(run.Worlds, save.FromWorld(world))
+	}
+	if err := save.Write(run); err != nil {
+		log.Printf("failed to save the run: %v", err)
+	}
+}
+
+// continueRun restores the saved run and removes the save, which is written
+// again if this run is quit too. The game starts paused.
+func (g *Game) continueRun() {
+	run, err := save.Read()
+	if err != nil {
+		log.Printf("failed to continue the run: %v", err)
+		g.hasSave = false
+		g.menuSelection = 0
+		return
+	}
+	g.mode = run.Mode
+	g.difficulty = run.Difficulty
+	g.turns = run.NewWorlds()
+	g.assisted = run.Assisted
+	g.currentTurn = run.CurrentTurn
+	g.stats = make([]turnStats, len(g.turns))
+	g.world = g.turns[g.currentTurn]
+	g.replay = run.Replay
+	if g.replay == nil {
+		g.replay = simulation.NewReplay(simulation.Setup{Mode: run.Mode, Difficulty: run.Difficulty, Players: len(g.world.Players)})
+	}
+	g.startBroadcast(g.replay.Setup, g.turns, g.currentTurn)
+	if err := save.Delete(); err != nil {
+		log.Printf("failed to remove the save: %v", err)
+	}
+	g.hasSave = false
+
+	if g.mode == GameMode.Alternating {
+		g.screen = Screen.Turn
+	} else {
+		g.screen = Screen.Play
+		g.paused = true
+	}
+}
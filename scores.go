@@ -0,0 +1,129 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/akshayxml/spaders/leaderboard"
+	"github.com/akshayxml/spaders/models/GameMode"
+)
+
+type submitResult struct {
+	result leaderboard.SubmitResult
+	err    error
+}
+
+type scoreSubmission struct {
+	result chan submitResult
+	status string
+}
+
+type topResult struct {
+	difficulty int
+	entries    []leaderboard.Entry
+	err        error
+}
+
+// topScores is the last page of the leaderboard fetched for the attract loop.
+type topScores struct {
+	result     chan topResult
+	difficulty int
+	entries    []leaderboard.Entry
+}
+
+// submitScore sends a finished single player game to the leaderboard, if one
+// is configured, without holding up the game over screen.
+func (g *Game) submitScore() {
+	if g.leaderboard == nil || g.mode != GameMode.Single || g.assisted {
+		return
+	}
+	var client = g.leaderboard
+	var sub = leaderboard.Submission{
+		Name:   g.config.PlayerName,
+		Score:  g.world.Score,
+		Replay: g.replay,
+	}
+	var result = make(chan submitResult, 1)
+	go func() {
+		res, err := client.Submit(sub)
+		result <- submitResult{res, err}
+	}()
+	g.submission = scoreSubmission{result: result, status: "SUBMITTING SCORE"}
+}
+
+func (g *Game) pollSubmission() {
+	if g.submission.result == nil {
+		return
+	}
+	select {
+	case res := <-g.submission.result:
+		g.submission.result = nil
+		if res.err != nil {
+			g.submission.status = "SCORE NOT SUBMITTED"
+			return
+		}
+		g.submission.status = "RANK " + strconv.Itoa(res.result.Rank) + " ON THE LEADERBOARD"
+	default:
+	}
+}
+
+// recordHighScore keeps the score of a finished single player game for the
+// high score page of the attract loop, which shows these when there is no
+// leaderboard to ask.
+func (g *Game) recordHighScore() {
+	if g.mode != GameMode.Single || g.assisted {
+		return
+	}
+	g.highScores = append(g.highScores, leaderboard.Entry{
+		Name:       g.config.PlayerName,
+		Score:      g.world.Score,
+		Difficulty: g.difficulty,
+	})
+	sort.SliceStable(g.highScores, func(i, j int) bool {
+		return g.highScores[i].Score > g.highScores[j].Score
+	})
+}
+
+func (g *Game) fetchHighScores() {
+	if g.leaderboard == nil || g.top.result != nil {
+		return
+	}
+	var client, difficulty = g.leaderboard, g.difficulty
+	var result = make(chan topResult, 1)
+	go func() {
+		entries, err := client.Top(difficulty, leaderboard.DefaultTop)
+		result <- topResult{difficulty, entries, err}
+	}()
+	g.top.result = result
+}
+
+func (g *Game) pollHighScores() {
+	if g.top.result == nil {
+		return
+	}
+	select {
+	case res := <-g.top.result:
+		g.top.result = nil
+		if res.err == nil {
+			g.top.difficulty = res.difficulty
+			g.top.entries = res.entries
+		}
+	default:
+	}
+}
+
+// highScoreTable returns the best scores for the selected difficulty, from
+// the leaderboard if it answered and from this session otherwise.
+func (g *Game) highScoreTable() (string, []leaderboard.Entry) {
+	var difficultyName = menuItems[g.difficulty-1]
+	if g.top.entries != nil && g.top.difficulty == g.difficulty {
+		return "LEADERBOARD " + difficultyName, g.top.entries
+	}
+	var entries []leaderboard.Entry
+	for _, entry := range g.highScores {
+		if entry.Difficulty == g.difficulty && len(entries) < leaderboard.DefaultTop {
+			entries = append(entries, entry)
+		}
+	}
+	return "HIGH SCORES " + difficultyName, entries
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/akshayxml/spaders/models/EnemyType"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// screenCanvas draws the parts of the screen shared with recordings, from
+// the render package, with ebiten.
+type screenCanvas struct {
+	screen *ebiten.Image
+}
+
+func (c screenCanvas) Rect(x, y, width, height float64, clr color.RGBA) {
+	ebitenutil.DrawRect(c.screen, x, y, width, height, clr)
+}
+
+func (c screenCanvas) Text(s string, x, y float64, clr color.RGBA) {
+	textOp := &text.DrawOptions{}
+	textOp.GeoM.Translate(x, y)
+	textOp.ColorScale.ScaleWithColor(clr)
+	text.Draw(c.screen, s, &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   normalFontSize,
+	}, textOp)
+}
+
+func (c screenCanvas) Enemy(t EnemyType.EnemyType, x, y, scale float64, tint color.RGBA) {
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(scale, scale)
+	opts.GeoM.Translate(x, y)
+	opts.ColorScale.ScaleWithColor(tint)
+	c.screen.DrawImage(enemyImages[t], opts)
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/akshayxml/spaders/input"
+	"github.com/akshayxml/spaders/models/Screen"
+	"github.com/akshayxml/spaders/theme"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+var actionLabels = map[input.Action]string{
+	input.MoveLeft:   "MOVE LEFT",
+	input.MoveRight:  "MOVE RIGHT",
+	input.Fire:       "FIRE",
+	input.Pause:      "PAUSE",
+	input.MenuUp:     "MENU UP",
+	input.MenuDown:   "MENU DOWN",
+	input.Confirm:    "CONFIRM",
+	input.Back:       "BACK",
+	input.Autopilot:  "AUTOPILOT",
+	input.Screenshot: "SCREENSHOT",
+	input.Record:     "RECORD CLIP",
+	input.Fullscreen: "FULLSCREEN",
+	input.Debug:      "DEBUG VIEW",
+	input.SlowMotion: "SLOW MOTION",
+	input.FrameStep:  "FRAME STEP",
+}
+
+const (
+	resetDefaultsLabel = "RESET DEFAULTS"
+	deadZoneLabel      = "STICK DEAD ZONE"
+	deadZoneStep       = 0.05
+	// The actions are listed in two columns so that they fit on the screen.
+	settingsRows = 9
+)
+
+type settingsState struct {
+	selection int
+	capturing bool
+	// Set when the last key pressed was already used by another action.
+	clash string
+}
+
+func (g *Game) deadZoneItem() int {
+	return len(input.Actions)
+}
+
+func (g *Game) resetDefaultsItem() int {
+	return len(input.Actions) + 1
+}
+
+func (g *Game) settingsItemCount() int {
+	return len(input.Actions) + 2
+}
+
+func (g *Game) saveConfig() {
+	g.config.Keyboard = g.actions.Bindings()
+	g.config.GamepadDeadZone = g.actions.DeadZone()
+	if err := g.config.Save(); err != nil {
+		log.Printf("failed to save config: %v", err)
+	}
+}
+
+func (g *Game) updateSettings() {
+	if g.settings.capturing {
+		if g.actions.IsJustPressed(input.Back) {
+			g.settings.capturing = false
+			g.settings.clash = ""
+			return
+		}
+		var keys = inpututil.AppendJustPressedKeys(nil)
+		if len(keys) > 0 {
+			var clash, ok = g.actions.Bind(input.Actions[g.settings.selection], keys[0])
+			if !ok {
+				var owner = actionLabels[clash.Action]
+				if clash.Partner {
+					owner = "PLAYER 2 " + owner
+				}
+				g.settings.clash = keyNames(keys[:1]) + " IS USED BY " + owner
+				return
+			}
+			g.settings.capturing = false
+			g.settings.clash = ""
+			g.saveConfig()
+		}
+		return
+	}
+
+	if g.actions.IsJustPressed(input.Back) {
+		g.screen = Screen.Menu
+		return
+	}
+	if g.actions.IsJustPressed(input.MenuDown) {
+		g.settings.selection = (g.settings.selection + 1) % g.settingsItemCount()
+	}
+	if g.actions.IsJustPressed(input.MenuUp) {
+		g.settings.selection = (g.settings.selection + g.settingsItemCount() - 1) % g.settingsItemCount()
+	}
+	if g.settings.selection == g.deadZoneItem() {
+		if g.actions.IsJustPressed(input.MoveLeft) {
+			g.actions.SetDeadZone(g.actions.DeadZone() - deadZoneStep)
+			g.saveConfig()
+		}
+		if g.actions.IsJustPressed(input.MoveRight) {
+			g.actions.SetDeadZone(g.actions.DeadZone() + deadZoneStep)
+			g.saveConfig()
+		}
+		return
+	}
+	if g.actions.IsJustPressed(input.Confirm) {
+		if g.settings.selection == g.resetDefaultsItem() {
+			g.actions.Reset()
+			g.saveConfig()
+		} else {
+			g.settings.capturing = true
+		}
+	}
+}
+
+func keyNames(keys []ebiten.Key) string {
+	var names = make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, strings.ToUpper(key.String()))
+	}
+	return strings.Join(names, " ")
+}
+
+func (g *Game) DrawSettings(screen *ebiten.Image, palette theme.Palette) {
+	face := &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   bigFontSize,
+	}
+	textOp := &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), 80)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	textOp.PrimaryAlign = text.AlignCenter
+	textOp.SecondaryAlign = text.AlignCenter
+	text.Draw(screen, "CONTROLS", face, textOp)
+
+	face = &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   normalFontSize,
+	}
+	for i, action := range input.Actions {
+		var label = actionLabels[action]
+		if g.settings.selection == i {
+			label = "->" + label
+		}
+		var keys = keyNames(g.actions.Keys(action))
+		if g.settings.selection == i && g.settings.capturing {
+			keys = "PRESS A KEY"
+		}
+		var x = 40 + 360*float64(i/settingsRows)
+		var y = 130 + float64(25*(i%settingsRows))
+
+		textOp = &text.DrawOptions{}
+		textOp.GeoM.Translate(x, y)
+		textOp.ColorScale.ScaleWithColor(palette.Accent)
+		text.Draw(screen, label, face, textOp)
+
+		textOp = &text.DrawOptions{}
+		textOp.GeoM.Translate(x+180, y)
+		textOp.ColorScale.ScaleWithColor(palette.Text)
+		text.Draw(screen, keys, face, textOp)
+	}
+
+	var y = 130 + float64(25*settingsRows) + 10
+	var label = deadZoneLabel
+	if g.settings.selection == g.deadZoneItem() {
+		label = "->" + label
+	}
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(40, y)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	text.Draw(screen, label, face, textOp)
+
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(280, y)
+	textOp.ColorScale.ScaleWithColor(palette.Text)
+	text.Draw(screen, fmt.Sprintf("< %.2f >", g.actions.DeadZone()), face, textOp)
+
+	label = resetDefaultsLabel
+	if g.settings.selection == g.resetDefaultsItem() {
+		label = "->" + label
+	}
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(40, y+25)
+	textOp.ColorScale.ScaleWithColor(palette.Accent)
+	text.Draw(screen, label, face, textOp)
+
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), logicalHeight-65)
+	textOp.ColorScale.ScaleWithColor(palette.Text)
+	textOp.PrimaryAlign = text.AlignCenter
+	var status = fmt.Sprintf("GAMEPADS CONNECTED %d", len(g.actions.Gamepads()))
+	if g.settings.clash != "" {
+		status = g.settings.clash
+	}
+	text.Draw(screen, status, face, textOp)
+
+	textOp = &text.DrawOptions{}
+	textOp.GeoM.Translate(float64(logicalWidth/2), logicalHeight-40)
+	textOp.ColorScale.ScaleWithColor(palette.Text)
+	textOp.PrimaryAlign = text.AlignCenter
+	var footer = "PRESS " + g.keyName(input.Back) + " TO GO BACK"
+	if g.settings.capturing {
+		footer = "PRESS " + g.keyName(input.Back) + " TO CANCEL"
+	}
+	text.Draw(screen, footer, face, textOp)
+}
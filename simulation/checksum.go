@@ -0,0 +1,60 @@
+package simulation
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+// Checksum hashes everything that Step reads or writes. Peers compare it to
+// detect when their simulations have drifted apart.
+func (w *World) Checksum() uint64 {
+	var h = fnv.New64a()
+	var buf [8]byte
+	var writeInt = func(v int64) {
+		binary.LittleEndian.PutUint64(buf[:], uint64(v))
+		h.Write(buf[:])
+	}
+	var writeFloat = func(v float64) {
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+		h.Write(buf[:])
+	}
+	var writeBool = func(v bool) {
+		if v {
+			writeInt(1)
+		} else {
+			writeInt(0)
+		}
+	}
+
+	writeInt(w.Tick)
+	writeInt(int64(w.rng.state))
+	writeInt(int64(w.Score))
+	for _, player := range w.Players {
+		writeFloat(player.Position.X)
+		writeFloat(player.Position.Y)
+		writeInt(int64(player.Lives))
+		writeBool(player.Bullet.IsActive)
+		writeFloat(player.Bullet.Position.X)
+		writeFloat(player.Bullet.Position.Y)
+	}
+	for _, enemy := range w.Enemies {
+		writeFloat(enemy.Position.X)
+		writeFloat(enemy.Position.Y)
+		writeInt(int64(enemy.State))
+	}
+	writeInt(int64(w.EnemyState.EnemyCount))
+	writeInt(int64(w.EnemyState.HorizontalDirection))
+	writeFloat(w.EnemyState.HorizontalSpeed)
+	writeInt(int64(w.EnemyState.EnemyFireRate))
+	for _, bullet := range w.EnemyBullets.Values() {
+		writeFloat(bullet.Position.X)
+		writeFloat(bullet.Position.Y)
+		writeBool(bullet.IsActive)
+	}
+	for _, bunkerSprite := range w.BunkerSprites {
+		writeFloat(bunkerSprite.Position.Y)
+		writeFloat(bunkerSprite.Height)
+	}
+	return h.Sum64()
+}
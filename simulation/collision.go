@@ -0,0 +1,242 @@
+package simulation
+
+import (
+	"math"
+
+	"github.com/akshayxml/spaders/broadphase"
+	"github.com/akshayxml/spaders/events"
+	"github.com/akshayxml/spaders/models"
+	"github.com/akshayxml/spaders/models/EntityState"
+	"github.com/akshayxml/spaders/sprites"
+)
+
+// Size of the cells of the broadphase grids. It is about the size of an
+// invader, so most bullets only ever look at one cell.
+const collisionCellSize = 32
+
+var playerBounds = spritesBounds(sprites.GetPlayerRectangles())
+
+func spritesBounds(rects []models.Rectangle) broadphase.AABB {
+	var bounds = broadphase.AABB{Left: rects[0].Position.X, Top: rects[0].Position.Y, Right: rects[0].Position.X, Bottom: rects[0].Position.Y}
+	for _, rect := range rects {
+		bounds.Left = min(bounds.Left, rect.Position.X)
+		bounds.Top = min(bounds.Top, rect.Position.Y)
+		bounds.Right = max(bounds.Right, rect.Position.X+rect.Width)
+		bounds.Bottom = max(bounds.Bottom, rect.Position.Y+rect.Height)
+	}
+	return bounds
+}
+
+func playerCenter(player *models.Player) models.Position {
+	return models.Position{
+		X: player.Position.X + (playerBounds.Left+playerBounds.Right)/2,
+		Y: player.Position.Y + (playerBounds.Top+playerBounds.Bottom)/2,
+	}
+}
+
+// bulletPath is the box around the path Bullet.CollisionTime tests.
+func bulletPath(b models.Bullet) broadphase.AABB {
+	var from, to = b.Path()
+	return broadphase.AABB{Left: min(from.X, to.X), Top: min(from.Y, to.Y), Right: max(from.X, to.X), Bottom: max(from.Y, to.Y)}
+}
+
+// indexColliders files the bunkers and invaders into grids, so that each
+// bullet is only tested against the few that are near it. Hits only ever
+// shrink bunkers and remove invaders, so the boxes stay big enough for the
+// rest of the tick.
+func (w *World) indexColliders() {
+	if w.bunkerIndex == nil {
+		w.bunkerIndex = broadphase.NewGrid(Width, Height, collisionCellSize)
+		w.enemyIndex = broadphase.NewGrid(Width, Height, collisionCellSize)
+	}
+	w.bunkerIndex.Clear()
+	for i, sprite := range w.BunkerSprites {
+		if sprite.Height > 0 {
+			w.bunkerIndex.Insert(i, broadphase.AABB{
+				Left:   sprite.Position.X,
+				Top:    sprite.Position.Y,
+				Right:  sprite.Position.X + sprite.Width,
+				Bottom: sprite.Position.Y + sprite.Height,
+			})
+		}
+	}
+	w.enemyIndex.Clear()
+	for i, enemy := range w.Enemies {
+		if enemy.State == EntityState.Alive {
+			w.enemyIndex.Insert(i, broadphase.AABB{
+				Left:   enemy.Position.X,
+				Top:    enemy.Position.Y,
+				Right:  enemy.Position.X + enemy.GetEnemyWidth(),
+				Bottom: enemy.Position.Y + enemy.GetEnemyHeight(),
+			})
+		}
+	}
+}
+
+// detectPlayerBulletCollision stops the bullet at the nearest bunker or
+// invader on its path, so that one bullet never hits two things.
+func (w *World) detectPlayerBulletCollision(p int, player *models.Player) {
+	var nearest, bunker, invader = math.Inf(1), -1, -1
+	w.candidates = w.bunkerIndex.Query(bulletPath(player.Bullet), w.candidates[:0])
+	for _, i := range w.candidates {
+		if w.BunkerSprites[i].Height > 0 {
+			var bunkerSpriteLeft = w.BunkerSprites[i].Position.X
+			var bunkerSpriteRight = w.BunkerSprites[i].Position.X + w.BunkerSprites[i].Width
+			var bunkerSpriteTop = w.BunkerSprites[i].Position.Y
+			var bunkerSpriteBottom = w.BunkerSprites[i].Position.Y + w.BunkerSprites[i].Height
+			if t, ok := player.Bullet.CollisionTime(bunkerSpriteLeft, bunkerSpriteRight, bunkerSpriteTop, bunkerSpriteBottom); ok && t < nearest {
+				nearest, bunker = t, i
+			}
+		}
+	}
+
+	w.candidates = w.enemyIndex.Query(bulletPath(player.Bullet), w.candidates[:0])
+	for _, i := range w.candidates {
+		var enemy = w.Enemies[i]
+		if enemy.State == EntityState.Alive {
+			if t, ok := player.Bullet.CollisionTime(enemy.Position.X, enemy.Position.X+enemy.GetEnemyWidth(), enemy.Position.Y, enemy.Position.Y+enemy.GetEnemyHeight()); ok && t < nearest {
+				nearest, bunker, invader = t, -1, i
+			}
+		}
+	}
+
+	if bunker >= 0 {
+		var bunkerSpriteBottom = w.BunkerSprites[bunker].Position.Y + w.BunkerSprites[bunker].Height
+		w.BunkerSprites[bunker].Height -= sprites.GetBunkerRectangles()[0].Height
+		player.Bullet.IsActive = false
+		w.publish(events.BunkerHit{Bunker: bunker, Position: models.Position{X: player.Bullet.Position.X, Y: bunkerSpriteBottom}})
+	}
+	if invader >= 0 {
+		var enemy = w.Enemies[invader]
+		var enemyLeftEdge = enemy.Position.X
+		var enemyRightEdge = enemy.Position.X + enemy.GetEnemyWidth()
+		var enemyTopEdge = enemy.Position.Y
+		var enemyBottomEdge = enemy.Position.Y + enemy.GetEnemyHeight()
+		player.Bullet.IsActive = false
+		w.Enemies[invader].State = EntityState.Dead
+		w.Score += EnemyPoints[enemy.Type]
+		w.EnemyState.EnemyCount--
+		w.publish(events.EnemyKilled{
+			Player:   p,
+			Enemy:    invader,
+			Type:     enemy.Type,
+			Position: models.Position{X: (enemyLeftEdge + enemyRightEdge) / 2, Y: (enemyTopEdge + enemyBottomEdge) / 2},
+			Points:   EnemyPoints[enemy.Type],
+		})
+		if w.EnemyState.EnemyCount == 0 {
+			w.publish(events.WaveCleared{Score: w.Score})
+		}
+	}
+
+	if player.Bullet.Position.Y <= 5 {
+		player.Bullet.IsActive = false
+	}
+}
+
+func (w *World) detectCollision() {
+	w.indexColliders()
+	for p, player := range w.Players {
+		if player.Bullet.IsActive {
+			w.detectPlayerBulletCollision(p, player)
+		}
+	}
+
+	for i := 0; i < w.EnemyBullets.Len(); i++ {
+		var bullet = w.EnemyBullets.At(i)
+		if bullet.IsActive {
+			w.detectEnemyBulletCollision(bullet)
+			if bullet.Position.Y >= Height-20 {
+				bullet.IsActive = false
+			}
+		}
+	}
+
+	for _, enemy := range w.Enemies {
+		for p, player := range w.Players {
+			if enemy.State == EntityState.Alive && player.Lives > 0 && enemy.Position.Y >= player.Position.Y {
+				player.Lives = 0
+				w.LifeLost = true
+				w.publish(events.PlayerHit{Player: p, Position: playerCenter(player), Lives: 0, Invaded: true})
+			}
+		}
+	}
+
+	var i = 0
+	for i < w.EnemyBullets.Len() {
+		if !w.EnemyBullets.At(i).IsActive {
+			w.Entities.Destroy(w.EnemyBullets.Entity(i))
+		} else {
+			i++
+		}
+	}
+}
+
+// detectEnemyBulletCollision stops the bullet at the nearest bunker, cannon
+// or player bullet on its path.
+func (w *World) detectEnemyBulletCollision(bullet *models.Bullet) {
+	var path = bulletPath(*bullet)
+	var nearest, bunker, cannon, playerBullet = math.Inf(1), -1, -1, -1
+	w.candidates = w.bunkerIndex.Query(path, w.candidates[:0])
+	for _, j := range w.candidates {
+		if w.BunkerSprites[j].Height > 0 {
+			var bunkerSpriteLeft = w.BunkerSprites[j].Position.X
+			var bunkerSpriteRight = w.BunkerSprites[j].Position.X + w.BunkerSprites[j].Width
+			var bunkerSpriteTop = w.BunkerSprites[j].Position.Y
+			var bunkerSpriteBottom = w.BunkerSprites[j].Position.Y + w.BunkerSprites[j].Height
+			if t, ok := bullet.CollisionTime(bunkerSpriteLeft, bunkerSpriteRight, bunkerSpriteTop, bunkerSpriteBottom); ok && t < nearest {
+				nearest, bunker = t, j
+			}
+		}
+	}
+
+	for p, player := range w.Players {
+		if player.Lives == 0 {
+			continue
+		}
+		// The edges are added up in a different order below, so the
+		// bounds are grown by a pixel to be sure they hold them.
+		var bounds = playerBounds
+		bounds.Left += player.Position.X - 1
+		bounds.Right += player.Position.X + 1
+		bounds.Top += player.Position.Y - 1
+		bounds.Bottom += player.Position.Y + 1
+		if bounds.Overlaps(path) {
+			for _, playerSprite := range sprites.GetPlayerRectangles() {
+				var playerLeftEdge = player.Position.X + playerSprite.Position.X
+				var playerRightEdge = player.Position.X + playerSprite.Position.X + playerSprite.Width
+				var playerTopEdge = player.Position.Y + playerSprite.Position.Y
+				var playerBottomEdge = player.Position.Y + playerSprite.Position.Y + playerSprite.Height
+				if t, ok := bullet.CollisionTime(playerLeftEdge, playerRightEdge, playerTopEdge, playerBottomEdge); ok && t < nearest {
+					nearest, bunker, cannon, playerBullet = t, -1, p, -1
+				}
+			}
+		}
+
+		if player.Bullet.IsActive {
+			if t, ok := player.Bullet.BulletCollisionTime(*bullet); ok && t < nearest {
+				nearest, bunker, cannon, playerBullet = t, -1, -1, p
+			}
+		}
+	}
+
+	switch {
+	case bunker >= 0:
+		var bunkerSpriteTop = w.BunkerSprites[bunker].Position.Y
+		w.BunkerSprites[bunker].Height -= sprites.GetBunkerRectangles()[0].Height
+		w.BunkerSprites[bunker].Position.Y += sprites.GetBunkerRectangles()[0].Height
+		bullet.IsActive = false
+		w.publish(events.BunkerHit{Bunker: bunker, Position: models.Position{X: bullet.Position.X, Y: bunkerSpriteTop}})
+	case cannon >= 0:
+		var player = w.Players[cannon]
+		player.Lives = max(player.Lives-1, 0)
+		bullet.IsActive = false
+		w.LifeLost = true
+		w.publish(events.PlayerHit{Player: cannon, Position: playerCenter(player), Lives: player.Lives})
+	case playerBullet >= 0:
+		var player = w.Players[playerBullet]
+		player.Bullet.IsActive = false
+		bullet.IsActive = false
+		w.Score += BulletPoints
+		w.publish(events.BulletsCollided{Player: playerBullet, Position: player.Bullet.Position, Points: BulletPoints})
+	}
+}
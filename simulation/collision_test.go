@@ -0,0 +1,162 @@
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akshayxml/spaders/broadphase"
+	"github.com/akshayxml/spaders/models"
+	"github.com/akshayxml/spaders/models/EntityState"
+	"github.com/akshayxml/spaders/sprites"
+)
+
+// everyBox hands back every box it holds, so that detectCollision tests
+// each bullet against every bunker and invader the way it did before the
+// grid.
+type everyBox struct {
+	ids []int
+}
+
+func (e *everyBox) Clear() {
+	e.ids = e.ids[:0]
+}
+
+func (e *everyBox) Insert(id int, box broadphase.AABB) {
+	e.ids = append(e.ids, id)
+}
+
+func (e *everyBox) Query(box broadphase.AABB, dst []int) []int {
+	return append(dst, e.ids...)
+}
+
+func newEveryBoxWorld(seed int64, difficulty, playerCount int) *World {
+	var w = New(seed, difficulty, playerCount)
+	w.bunkerIndex, w.enemyIndex = &everyBox{}, &everyBox{}
+	return w
+}
+
+// flood keeps the players alive and the air full of enemy bullets, many
+// more than a normal game has, topping them up from rng as they land.
+func flood(w *World, bullets int, rng *rand.Rand) {
+	var height = GetSpritesHeight(sprites.GetEnemyBulletRectangles())
+	for _, player := range w.Players {
+		player.Lives = 3
+	}
+	for w.EnemyBullets.Len() < bullets {
+		w.SpawnEnemyBullet(models.Bullet{
+			Position:  models.Position{X: rng.Float64() * Width, Y: rng.Float64() * Height / 2},
+			Direction: 1,
+			Speed:     2,
+			IsActive:  true,
+			Height:    height,
+		})
+	}
+}
+
+func TestGridMatchesEveryBox(t *testing.T) {
+	for seed := int64(1); seed <= 3; seed++ {
+		var grid, every = New(seed, 2, 2), newEveryBoxWorld(seed, 2, 2)
+		var gridRng, everyRng = rand.New(rand.NewSource(seed)), rand.New(rand.NewSource(seed))
+		var inputs = []Input{InputFire | InputLeft, InputFire | InputRight}
+		for tick := 0; tick < 2000 && grid.CanPlay(); tick++ {
+			flood(grid, 200, gridRng)
+			flood(every, 200, everyRng)
+			grid.Step(inputs)
+			every.Step(inputs)
+			if grid.Checksum() != every.Checksum() {
+				t.Fatalf("seed %d: worlds differ after tick %d", seed, grid.Tick)
+			}
+			if !reflect.DeepEqual(grid.Events, every.Events) {
+				t.Fatalf("seed %d: tick %d published %v, want %v", seed, grid.Tick, grid.Events, every.Events)
+			}
+		}
+	}
+}
+
+// A bullet fast enough to cross two invaders in one tick only kills the
+// first of them.
+func TestBulletStopsAtNearestHit(t *testing.T) {
+	var w = New(1, 1, 1)
+	w.BunkerSprites = nil
+	w.Enemies = []models.Enemy{
+		{Position: models.Position{X: 100, Y: 100}, Scale: 0.5, State: EntityState.Alive},
+		{Position: models.Position{X: 100, Y: 200}, Scale: 0.5, State: EntityState.Alive},
+	}
+	w.EnemyState.EnemyCount = 2
+	var player = w.Players[0]
+	player.Bullet = models.Bullet{
+		Previous:  models.Position{X: 105, Y: 300},
+		Position:  models.Position{X: 105, Y: 50},
+		Direction: -1,
+		IsActive:  true,
+		Height:    10,
+	}
+	w.detectCollision()
+	if w.Enemies[0].State != EntityState.Alive || w.Enemies[1].State != EntityState.Dead {
+		t.Errorf("invader states %v and %v, want only the nearer one dead", w.Enemies[0].State, w.Enemies[1].State)
+	}
+	if w.EnemyState.EnemyCount != 1 || len(w.Events) != 1 {
+		t.Errorf("%d invaders left and events %v, want 1 and one kill", w.EnemyState.EnemyCount, w.Events)
+	}
+}
+
+// An enemy bullet crossing a bunker and the cannon below it in one tick
+// only chips the bunker.
+func TestEnemyBulletStopsAtBunker(t *testing.T) {
+	var w = New(1, 1, 1)
+	var player = w.Players[0]
+	var bunker = models.Rectangle{Position: models.Position{X: player.Position.X, Y: player.Position.Y - 30}, Width: 100, Height: 10}
+	w.BunkerSprites = []models.Rectangle{bunker}
+	var x = playerCenter(player).X
+	w.SpawnEnemyBullet(models.Bullet{
+		Previous:  models.Position{X: x, Y: bunker.Position.Y - 20},
+		Position:  models.Position{X: x, Y: player.Position.Y + 5},
+		Direction: 1,
+		IsActive:  true,
+		Height:    5,
+	})
+	var lives = player.Lives
+	w.detectCollision()
+	if player.Lives != lives || w.LifeLost {
+		t.Errorf("cannon lost a life through the bunker")
+	}
+	if w.BunkerSprites[0].Height >= bunker.Height {
+		t.Errorf("bunker was not hit")
+	}
+}
+
+// benchmarkFloodedTicks times whole ticks of co-op on MEDIUM with the air
+// full of bullets, and reports them as a share of the time a tick has at
+// 60 ticks a second.
+func benchmarkFloodedTicks(b *testing.B, newWorld func(seed int64, difficulty, playerCount int) *World) {
+	for _, bullets := range []int{50, 200, 1000} {
+		b.Run(fmt.Sprintf("bullets=%d", bullets), func(b *testing.B) {
+			var rng = rand.New(rand.NewSource(1))
+			var w = newWorld(1, 2, 2)
+			var inputs = []Input{InputFire, InputFire}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if !w.CanPlay() {
+					b.StopTimer()
+					w = newWorld(int64(i), 2, 2)
+					b.StartTimer()
+				}
+				flood(w, bullets, rng)
+				w.Step(inputs)
+			}
+			var budget = time.Second / TicksPerSecond
+			b.ReportMetric(100*float64(b.Elapsed())/float64(b.N)/float64(budget), "%budget")
+		})
+	}
+}
+
+func BenchmarkTickGrid(b *testing.B) {
+	benchmarkFloodedTicks(b, New)
+}
+
+func BenchmarkTickEveryBox(b *testing.B) {
+	benchmarkFloodedTicks(b, newEveryBoxWorld)
+}
@@ -0,0 +1,9 @@
+package simulation
+
+import "github.com/akshayxml/spaders/events"
+
+// publish records an event for the tick being played. Events never change
+// how the game plays.
+func (w *World) publish(e events.Event) {
+	w.Events = append(w.Events, e)
+}
@@ -0,0 +1,13 @@
+package simulation
+
+type Input uint8
+
+const (
+	InputLeft Input = 1 << iota
+	InputRight
+	InputFire
+)
+
+func (i Input) Has(flag Input) bool {
+	return i&flag != 0
+}
@@ -0,0 +1,90 @@
+package simulation
+
+import "github.com/akshayxml/spaders/models/GameMode"
+
+// Match holds the worlds of a two-player game. Co-op shares one world between
+// both cannons; in versus each player gets their own world with the same
+// seed.
+type Match struct {
+	Worlds []*World
+	Mode   GameMode.GameMode
+	slots  map[int][]*World
+}
+
+func NewMatch(seed int64, difficulty int, mode GameMode.GameMode) *Match {
+	var m = &Match{Mode: mode, slots: map[int][]*World{}}
+	if mode == GameMode.Versus {
+		m.Worlds = []*World{New(seed, difficulty, 1), New(seed, difficulty, 1)}
+	} else {
+		m.Worlds = []*World{New(seed, difficulty, 2)}
+	}
+	return m
+}
+
+// World returns the world player plays in.
+func (m *Match) World(player int) *World {
+	if m.Mode == GameMode.Versus {
+		return m.Worlds[player]
+	}
+	return m.Worlds[0]
+}
+
+// Player returns the cannon of player.
+func (m *Match) Player(player int) int {
+	if m.Mode == GameMode.Versus {
+		return 0
+	}
+	return player
+}
+
+// WorldInputs splits the inputs of both players into the inputs of each
+// world.
+func (m *Match) WorldInputs(inputs []Input) Frame {
+	if m.Mode == GameMode.Versus {
+		return Frame{{inputs[0]}, {inputs[1]}}
+	}
+	return Frame{inputs}
+}
+
+func (m *Match) Step(inputs []Input) {
+	for i, worldInputs := range m.WorldInputs(inputs) {
+		m.Worlds[i].Step(worldInputs)
+	}
+}
+
+func (m *Match) Save(slot int) {
+	var saved, ok = m.slots[slot]
+	if !ok {
+		for _, world := range m.Worlds {
+			saved = append(saved, world.Clone())
+		}
+		m.slots[slot] = saved
+		return
+	}
+	for i, world := range m.Worlds {
+		saved[i].CopyFrom(world)
+	}
+}
+
+func (m *Match) Load(slot int) {
+	for i, world := range m.Worlds {
+		world.CopyFrom(m.slots[slot][i])
+	}
+}
+
+func (m *Match) Checksum() uint64 {
+	var checksum uint64
+	for _, world := range m.Worlds {
+		checksum = checksum*31 + world.Checksum()
+	}
+	return checksum
+}
+
+func (m *Match) IsOver() bool {
+	for _, world := range m.Worlds {
+		if world.CanPlay() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/akshayxml/spaders/models/GameMode"
+)
+
+// ReplayVersion changes whenever the same inputs would play out differently.
+// Version 2 checks collisions along the whole path of every bullet, and
+// version 3 stops every bullet at the first thing on its path.
+const ReplayVersion = 3
+
+// Setup is everything needed to create the worlds of a game, one for each
+// seed. Worlds created from the same setup and fed the same inputs end up
+// identical.
+type Setup struct {
+	Mode       GameMode.GameMode `json:"mode"`
+	Difficulty int               `json:"difficulty"`
+	Seeds      []int64           `json:"seeds"`
+	Players    int               `json:"players"`
+}
+
+func (s Setup) NewWorlds() []*World {
+	var worlds = make([]*World, len(s.Seeds))
+	for i, seed := range s.Seeds {
+		worlds[i] = New(seed, s.Difficulty, s.Players)
+		worlds[i].TakesTurns = s.Mode == GameMode.Alternating
+	}
+	return worlds
+}
+
+// A Frame holds the inputs of one tick for every world. Worlds that did not
+// step that tick, like the waiting player's in alternating mode, have none.
+type Frame [][]Input
+
+func (f Frame) equal(other Frame) bool {
+	if len(f) != len(other) {
+		return false
+	}
+	for i := range f {
+		if len(f[i]) != len(other[i]) {
+			return false
+		}
+		for j := range f[i] {
+			if f[i][j] != other[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// A Run is a stretch of consecutive ticks with the same inputs. Players hold
+// keys for many ticks at a time, so this keeps replays small.
+type Run struct {
+	Ticks int   `json:"ticks"`
+	Frame Frame `json:"frame"`
+}
+
+// Replay records every input of a game so that it can be simulated again.
+type Replay struct {
+	Version int   `json:"version"`
+	Setup   Setup `json:"setup"`
+	Runs    []Run `json:"runs"`
+}
+
+func NewReplay(setup Setup) *Replay {
+	return &Replay{Version: ReplayVersion, Setup: setup}
+}
+
+func (r *Replay) Record(frame Frame) {
+	if last := len(r.Runs) - 1; last >= 0 && r.Runs[last].Frame.equal(frame) {
+		r.Runs[last].Ticks++
+		return
+	}
+	var copied = make(Frame, len(frame))
+	for i, inputs := range frame {
+		copied[i] = append([]Input{}, inputs...)
+	}
+	r.Runs = append(r.Runs, Run{Ticks: 1, Frame: copied})
+}
+
+func (r *Replay) Ticks() int64 {
+	var ticks int64
+	for _, run := range r.Runs {
+		ticks += int64(run.Ticks)
+	}
+	return ticks
+}
+
+// ErrReplayTooLong is returned by PlayUpTo for replays longer than allowed.
+var ErrReplayTooLong = errors.New("simulation: replay is too long")
+
+// Play simulates the whole replay and returns the worlds as they were at the
+// end of it.
+func (r *Replay) Play() ([]*World, error) {
+	return r.watch(math.MaxInt64, func(*World) {})
+}
+
+// PlayUpTo is Play for replays that can't be trusted, like the ones sent to
+// the leaderboard. It stops with ErrReplayTooLong before simulating past
+// limit ticks.
+func (r *Replay) PlayUpTo(limit int64) ([]*World, error) {
+	return r.watch(limit, func(*World) {})
+}
+
+// Watch simulates the replay like Play and calls f after every tick with
+// the world that stepped, or the first one when several did.
+func (r *Replay) Watch(f func(*World)) ([]*World, error) {
+	return r.watch(math.MaxInt64, f)
+}
+
+func (r *Replay) watch(limit int64, f func(*World)) ([]*World, error) {
+	if r.Version != ReplayVersion {
+		return nil, fmt.Errorf("simulation: replay version %d, want %d", r.Version, ReplayVersion)
+	}
+	if len(r.Setup.Seeds) == 0 || r.Setup.Players < 1 {
+		return nil, errors.New("simulation: replay has no worlds")
+	}
+	var worlds = r.Setup.NewWorlds()
+	var total int64
+	for _, run := range r.Runs {
+		if len(run.Frame) != len(worlds) {
+			return nil, errors.New("simulation: replay frame does not match its worlds")
+		}
+		// Runs are checked one at a time as they are played, so that a
+		// negative run can't hide a huge one from the total.
+		if run.Ticks < 1 {
+			return nil, fmt.Errorf("simulation: replay has a run of %d ticks", run.Ticks)
+		}
+		total += int64(run.Ticks)
+		if total > limit {
+			return nil, ErrReplayTooLong
+		}
+		for tick := 0; tick < run.Ticks; tick++ {
+			var stepped *World
+			for i, inputs := range run.Frame {
+				if len(inputs) > 0 {
+					worlds[i].Step(inputs)
+					if stepped == nil {
+						stepped = worlds[i]
+					}
+				}
+			}
+			if stepped != nil {
+				f(stepped)
+			}
+		}
+	}
+	return worlds, nil
+}
@@ -0,0 +1,23 @@
+package simulation
+
+// rng is a splitmix64 generator. Unlike math/rand its whole state is a single
+// word, so snapshotting a World also snapshots its random numbers.
+type rng struct {
+	state uint64
+}
+
+func newRng(seed int64) rng {
+	return rng{state: uint64(seed)}
+}
+
+func (r *rng) next() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	var z = r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (r *rng) Intn(n int) int {
+	return int(r.next() % uint64(n))
+}
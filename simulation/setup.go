@@ -0,0 +1,107 @@
+package simulation
+
+import (
+	"github.com/akshayxml/spaders/models"
+	"github.com/akshayxml/spaders/models/EnemyType"
+	"github.com/akshayxml/spaders/models/EntityState"
+	"github.com/akshayxml/spaders/sprites"
+)
+
+func GetSpritesWidth(sprites []models.Rectangle) float64 {
+	var width = 0.0
+	for _, sprite := range sprites {
+		width += sprite.Width
+	}
+	return width
+}
+
+func GetSpritesHeight(sprites []models.Rectangle) float64 {
+	var height = 0.0
+	for _, sprite := range sprites {
+		height += sprite.Width
+	}
+	return height
+}
+
+func getEnemies(rows int, yPosStart float64, enemyType EnemyType.EnemyType, scale float64) (float64, []models.Enemy) {
+	var cols = 10
+	var imgWidth = models.EnemyImgWidth * scale
+	var imgHeight = models.EnemyImgHeight * scale
+	var xPosStart = float64(Width/2) - (imgWidth / 2) - 40 - imgWidth*5
+	var xGap = imgWidth + 10
+	var yGap = imgHeight + 10
+	var enemies = []models.Enemy{}
+
+	for y, rowCnt := float64(yPosStart), 0; y < float64(Height) && rowCnt < rows; y, rowCnt = y+yGap, rowCnt+1 {
+		for x, colCnt := float64(xPosStart), 0; x < float64(Width) && colCnt < cols; x, colCnt = x+xGap, colCnt+1 {
+			var enemy = models.Enemy{Position: models.Position{X: x, Y: y}, Type: enemyType, Scale: scale, State: EntityState.Alive}
+			enemies = append(enemies, enemy)
+		}
+	}
+
+	return yGap * float64(rows), enemies
+}
+
+func setupEnemies() []models.Enemy {
+	var allEnemies = []models.Enemy{}
+	var enemyYPos float64 = 60
+	var yGap, enemies = getEnemies(1, enemyYPos, EnemyType.Three, 0.5)
+	allEnemies = append(allEnemies, enemies[:]...)
+
+	enemyYPos += yGap
+	yGap, enemies = getEnemies(2, enemyYPos, EnemyType.Two, 0.6)
+	allEnemies = append(allEnemies, enemies[:]...)
+
+	enemyYPos += yGap
+	yGap, enemies = getEnemies(2, enemyYPos, EnemyType.One, 0.6)
+	allEnemies = append(allEnemies, enemies[:]...)
+
+	return allEnemies
+}
+
+func setupBunkers() []models.Rectangle {
+	var imgWidth float64 = 0
+	for _, rect := range sprites.GetBunkerRectangles() {
+		imgWidth += rect.Width
+	}
+	bunkerPositions := []struct{ x, y float64 }{
+		{float64(Width/5 - float64(imgWidth/2)), float64(Height - 100)},
+		{float64(2*(Width/5) - float64(imgWidth/2)), float64(Height - 100)},
+		{float64(3*(Width/5) - float64(imgWidth/2)), float64(Height - 100)},
+		{float64(4*(Width/5) - float64(imgWidth/2)), float64(Height - 100)},
+	}
+	var bunkerSprites = []models.Rectangle{}
+	for i := range bunkerPositions {
+		for _, bunkerSprite := range sprites.GetBunkerRectangles() {
+			bunkerSprite.Position.X += bunkerPositions[i].x
+			bunkerSprite.Position.Y += bunkerPositions[i].y
+			bunkerSprites = append(bunkerSprites, bunkerSprite)
+		}
+	}
+
+	return bunkerSprites
+}
+
+func setupPlayers(playerCount, difficulty int) []*models.Player {
+	var players = []*models.Player{}
+	for i := 0; i < playerCount; i++ {
+		var player = &models.Player{
+			Position: models.Position{
+				X: Width * float64(i+1) / float64(playerCount+1),
+				Y: Height - 40,
+			},
+			Lives: 3,
+			Speed: 2.0,
+			Bullet: models.Bullet{
+				Direction: -1,
+				Speed:     3,
+				IsActive:  false,
+			},
+		}
+		if difficulty == 3 {
+			player.Lives = 1
+		}
+		players = append(players, player)
+	}
+	return players
+}
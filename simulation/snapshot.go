@@ -0,0 +1,53 @@
+package simulation
+
+import "github.com/akshayxml/spaders/models"
+
+func (w *World) Clone() *World {
+	var clone = &World{}
+	for range w.Players {
+		clone.Players = append(clone.Players, &models.Player{})
+	}
+	clone.CopyFrom(w)
+	return clone
+}
+
+// CopyFrom overwrites w with src while reusing w's memory, so that rolling
+// back every tick does not allocate. Pointers into w, such as its players,
+// stay valid.
+func (w *World) CopyFrom(src *World) {
+	for len(w.Players) < len(src.Players) {
+		w.Players = append(w.Players, &models.Player{})
+	}
+	w.Players = w.Players[:len(src.Players)]
+	for i := range src.Players {
+		*w.Players[i] = *src.Players[i]
+	}
+	w.Enemies = append(w.Enemies[:0], src.Enemies...)
+	w.BunkerSprites = append(w.BunkerSprites[:0], src.BunkerSprites...)
+
+	w.EnemyState = src.EnemyState
+	if w.Entities == nil {
+		w.initEntities()
+	}
+	w.Entities.CopyFrom(src.Entities)
+	w.EnemyBullets.CopyFrom(src.EnemyBullets)
+
+	w.Events = append(w.Events[:0], src.Events...)
+	w.Score = src.Score
+	w.Difficulty = src.Difficulty
+	w.Tick = src.Tick
+	w.LifeLost = src.LifeLost
+	w.Tuning = src.Tuning
+	w.TakesTurns = src.TakesTurns
+	w.rng = src.rng
+}
+
+// RandomState and SetRandomState expose the random number generator, which
+// is all a saved world needs besides its exported fields.
+func (w *World) RandomState() uint64 {
+	return w.rng.state
+}
+
+func (w *World) SetRandomState(state uint64) {
+	w.rng.state = state
+}
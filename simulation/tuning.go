@@ -0,0 +1,23 @@
+package simulation
+
+// Tuning holds the constants the difficulty ramp is built from. Every world
+// starts with DefaultTuning; cmd/simulate changes it to try out other values
+// before they are made the default.
+type Tuning struct {
+	// Horizontal speed the formation speeds up to, plus one on DEATHZONE.
+	HorizontalSpeedLimit            float64
+	HorizontalSpeedChangeIntervalMs int
+	// How often the formation steps down towards the cannon.
+	VerticalMoveIntervalMs int
+	// Chance in a hundred of an enemy firing each tick, times the difficulty.
+	FireRateLimit                 int
+	FireRateLimitChangeIntervalMs int
+}
+
+var DefaultTuning = Tuning{
+	HorizontalSpeedLimit:            2.0,
+	HorizontalSpeedChangeIntervalMs: 10000,
+	VerticalMoveIntervalMs:          15000,
+	FireRateLimit:                   10,
+	FireRateLimitChangeIntervalMs:   10000,
+}
@@ -0,0 +1,249 @@
+package simulation
+
+import (
+	"github.com/akshayxml/spaders/broadphase"
+	"github.com/akshayxml/spaders/ecs"
+	"github.com/akshayxml/spaders/events"
+	"github.com/akshayxml/spaders/models"
+	"github.com/akshayxml/spaders/models/EnemyType"
+	"github.com/akshayxml/spaders/models/EntityState"
+	"github.com/akshayxml/spaders/sprites"
+)
+
+const (
+	Width          float64 = 640
+	Height         float64 = 480
+	LeftBoundary           = 50
+	RightBoundary          = Width - 80
+	TicksPerSecond         = 60
+	// Points for shooting down an enemy bullet.
+	BulletPoints = 3
+)
+
+// EnemyPoints is what each type of invader is worth.
+var EnemyPoints = map[EnemyType.EnemyType]int{
+	EnemyType.One:   5,
+	EnemyType.Two:   5,
+	EnemyType.Three: 5,
+}
+
+// World is one run of the game. It only changes through Step, so two worlds
+// created with the same seed and fed the same inputs stay identical.
+type World struct {
+	// The cannons, invaders and bunkers are fixed for the whole game and
+	// stay in slices rather than ECS stores: their index is who they are in
+	// events, saves and gym observations, and a store reorders what is left
+	// when something is removed. Each player has its one bullet.
+	Players       []*models.Player
+	Enemies       []models.Enemy
+	EnemyState    models.EnemyState
+	BunkerSprites []models.Rectangle
+	Score         int
+	Difficulty    int
+	Tick          int64
+	LifeLost      bool
+	Tuning        Tuning
+	// TakesTurns is set on the worlds of an alternating game. Play passes
+	// to the other world after every tick a life is lost in, and the
+	// bullets still in flight are cleared at the end of that tick, so that
+	// the turn is handed over the same way in replays and for spectators.
+	TakesTurns bool
+	// Events are what happened during the last tick.
+	Events []events.Event
+	// Entities are the objects that come and go during a game, like enemy
+	// bullets. Each kind has a store of its components.
+	Entities     *ecs.Registry
+	EnemyBullets *ecs.Store[models.Bullet]
+	rng          rng
+	inputs       []Input
+	// Scratch space for collision detection, rebuilt every tick.
+	bunkerIndex broadphase.Index
+	enemyIndex  broadphase.Index
+	candidates  []int
+}
+
+func New(seed int64, difficulty, playerCount int) *World {
+	var w = &World{
+		Players:       setupPlayers(playerCount, difficulty),
+		Enemies:       setupEnemies(),
+		BunkerSprites: setupBunkers(),
+		Difficulty:    difficulty,
+		Tuning:        DefaultTuning,
+		rng:           newRng(seed),
+	}
+	w.EnemyState = models.EnemyState{
+		HorizontalDirection: 1,
+		HorizontalSpeed:     1.0,
+		EnemyCount:          len(w.Enemies),
+		EnemyFireRate:       1,
+	}
+	w.initEntities()
+	return w
+}
+
+func (w *World) initEntities() {
+	w.Entities = ecs.NewRegistry()
+	w.EnemyBullets = ecs.NewStore[models.Bullet](w.Entities)
+}
+
+// systems are the parts of a tick in the order they run. A new kind of
+// entity gets a store on World and a system here.
+var systems = ecs.Systems[*World]{
+	(*World).applyInputs,
+	(*World).moveEnemySideways,
+	(*World).generateEnemyBullets,
+	(*World).moveBullets,
+	(*World).updateDifficulty,
+	(*World).detectCollision,
+}
+
+// ElapsedMs is the play time the difficulty ramp is based on.
+func (w *World) ElapsedMs() int64 {
+	return w.Tick * 1000 / TicksPerSecond
+}
+
+func (w *World) CanPlay() bool {
+	if w.EnemyState.EnemyCount == 0 {
+		return false
+	}
+	for _, player := range w.Players {
+		if player.Lives > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Step advances the world by one tick. inputs holds one entry per player;
+// missing entries are treated as no input.
+func (w *World) Step(inputs []Input) {
+	w.LifeLost = false
+	w.Events = w.Events[:0]
+	w.inputs = inputs
+	var playing = w.CanPlay()
+	systems.Run(w)
+	w.inputs = nil
+	if w.TakesTurns && (w.LifeLost || !w.CanPlay()) {
+		w.clearBullets()
+	}
+	if playing && !w.CanPlay() {
+		w.publish(events.GameOver{Score: w.Score, Cleared: w.EnemyState.EnemyCount == 0})
+	}
+	w.Tick++
+}
+
+func (w *World) applyInputs() {
+	for i, player := range w.Players {
+		if player.Lives > 0 && i < len(w.inputs) {
+			w.applyInput(player, w.inputs[i])
+		}
+	}
+}
+
+func (w *World) applyInput(player *models.Player, input Input) {
+	if input.Has(InputLeft) {
+		player.MoveLeft()
+	}
+	if input.Has(InputRight) {
+		player.MoveRight(RightBoundary)
+	}
+	if input.Has(InputFire) && !player.Bullet.IsActive {
+		player.Bullet.Position = models.Position{X: player.Position.X + 20, Y: player.Position.Y}
+		player.Bullet.Height = GetSpritesHeight(sprites.GetPlayerBulletRectangles())
+		player.Bullet.Fire()
+	}
+}
+
+func (w *World) moveEnemySideways() {
+	leftMostEnemy := Width
+	rightMostEnemy := 0.0
+	for i := range w.Enemies {
+		if w.Enemies[i].State == EntityState.Alive {
+			leftMostEnemy = min(leftMostEnemy, w.Enemies[i].Position.X)
+			rightMostEnemy = max(rightMostEnemy, w.Enemies[i].Position.X)
+		}
+	}
+
+	if leftMostEnemy < LeftBoundary {
+		w.EnemyState.HorizontalDirection = 1
+	} else if rightMostEnemy >= RightBoundary {
+		w.EnemyState.HorizontalDirection = -1
+	}
+
+	for i := range w.Enemies {
+		if w.Enemies[i].State == EntityState.Alive {
+			w.Enemies[i].Position.X += w.EnemyState.HorizontalSpeed * float64(w.EnemyState.HorizontalDirection)
+		}
+	}
+}
+
+func (w *World) moveBullets() {
+	for _, player := range w.Players {
+		if player.Bullet.IsActive {
+			player.Bullet.Move()
+		}
+	}
+
+	for i := 0; i < w.EnemyBullets.Len(); i++ {
+		w.EnemyBullets.At(i).Move()
+	}
+}
+
+func (w *World) SpawnEnemyBullet(bullet models.Bullet) ecs.Entity {
+	var e = w.Entities.Create()
+	w.EnemyBullets.Add(e, bullet)
+	return e
+}
+
+// clearBullets removes every bullet in flight before the turn is handed to
+// the other player.
+func (w *World) clearBullets() {
+	for _, player := range w.Players {
+		player.Bullet.IsActive = false
+	}
+	for w.EnemyBullets.Len() > 0 {
+		w.Entities.Destroy(w.EnemyBullets.Entity(w.EnemyBullets.Len() - 1))
+	}
+}
+
+func (w *World) generateEnemyBullets() {
+	if w.rng.Intn(100) <= w.EnemyState.EnemyFireRate {
+		var enemyNumber = w.rng.Intn(len(w.Enemies))
+		if w.Enemies[enemyNumber].State == EntityState.Alive {
+			var enemyWidth = w.Enemies[enemyNumber].GetEnemyWidth()
+			var enemyHeight = w.Enemies[enemyNumber].GetEnemyHeight()
+			var bullet = models.Bullet{
+				Position: models.Position{X: w.Enemies[enemyNumber].Position.X + enemyWidth/2,
+					Y: w.Enemies[enemyNumber].Position.Y + enemyHeight/2},
+				Direction: 1,
+				Speed:     w.Difficulty,
+				IsActive:  true,
+				Height:    GetSpritesHeight(sprites.GetEnemyBulletRectangles()),
+			}
+			w.SpawnEnemyBullet(bullet)
+		}
+	}
+}
+
+func (w *World) updateDifficulty() {
+	var elapsedTime = w.ElapsedMs()
+	var baseHorizontalSpeedLimit = w.Tuning.HorizontalSpeedLimit
+	var baseHorizontalSpeedChangeIntervalMs = w.Tuning.HorizontalSpeedChangeIntervalMs
+	var baseVerticalMoveIntervalMs = w.Tuning.VerticalMoveIntervalMs
+	var baseFireRateLimit = w.Tuning.FireRateLimit
+	var baseFireRateLimitChangeIntervalMs = w.Tuning.FireRateLimitChangeIntervalMs
+
+	for i := range w.Enemies {
+		var verticalMoveIntervalMs = int64(baseVerticalMoveIntervalMs - ((baseVerticalMoveIntervalMs / 3) * (w.Difficulty - 1)))
+		if elapsedTime >= verticalMoveIntervalMs && elapsedTime%verticalMoveIntervalMs <= 100 {
+			w.Enemies[i].Position.Y += min(2.5, float64(w.Difficulty))
+		}
+	}
+
+	var horizontalSpeedChangeIntervalMs = int64(baseHorizontalSpeedChangeIntervalMs - ((baseHorizontalSpeedChangeIntervalMs / 3) * (w.Difficulty - 1)))
+	var horizontalSpeedLimit = baseHorizontalSpeedLimit + float64(w.Difficulty/2)
+	w.EnemyState.HorizontalSpeed = min(horizontalSpeedLimit, 1+float64(elapsedTime)/float64(horizontalSpeedChangeIntervalMs*10))
+
+	var fireRateLimitChangeIntervalMs = int64(baseFireRateLimitChangeIntervalMs - ((baseFireRateLimitChangeIntervalMs / 3) * (w.Difficulty - 1)))
+	w.EnemyState.EnemyFireRate = min(baseFireRateLimit*w.Difficulty, int(elapsedTime/fireRateLimitChangeIntervalMs))
+}
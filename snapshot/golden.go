@@ -0,0 +1,82 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Golden files are real files of every version a schema has had, kept in a
+// directory as <kind>-v<version>.json. Files of older versions must keep
+// decoding to the same value as the file of the current version.
+
+func (s *Schema) goldenPath(dir string, version int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-v%d.json", s.Kind, version))
+}
+
+// decodeGolden returns the golden file of version decoded and encoded again
+// as plain JSON, or nil if there is no such file.
+func (s *Schema) decodeGolden(dir string, version int) ([]byte, error) {
+	data, err := os.ReadFile(s.goldenPath(dir, version))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var v = s.newValue()
+	if err := s.Decode(data, v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func (s *Schema) CheckGolden(dir string) error {
+	current, err := s.decodeGolden(dir, s.Version)
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.goldenPath(dir, s.Version), err)
+	}
+	if current == nil {
+		return fmt.Errorf("%s: missing golden file for the current version", s.goldenPath(dir, s.Version))
+	}
+
+	var errs []error
+	for version := 0; version < s.Version; version++ {
+		old, err := s.decodeGolden(dir, version)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.goldenPath(dir, version), err))
+		} else if old != nil && !bytes.Equal(old, current) {
+			errs = append(errs, fmt.Errorf("%s: does not decode to the same value as version %d", s.goldenPath(dir, version), s.Version))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UpdateGolden writes the golden file of the current version by migrating
+// the newest older one, which is how a golden file is made after a schema
+// changes.
+func (s *Schema) UpdateGolden(dir string) error {
+	for version := s.Version - 1; version >= 0; version-- {
+		data, err := os.ReadFile(s.goldenPath(dir, version))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var v = s.newValue()
+		if err := s.Decode(data, v); err != nil {
+			return err
+		}
+		data, err = s.Encode(v)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(s.goldenPath(dir, s.Version), append(data, '\n'), 0o644)
+	}
+	return fmt.Errorf("snapshot: no golden file of %s to update from", s.Kind)
+}
@@ -0,0 +1,132 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrTooNew = errors.New("snapshot: written by a newer version of the game")
+
+// A Migration upgrades the data of a document by one version in place.
+// Numbers are json.Number so that 64-bit values such as seeds survive.
+type Migration func(doc map[string]any) error
+
+// Schema is a versioned on-disk format. Files are wrapped in an envelope
+// naming their kind and version, and files of an older version are brought
+// up to date by running the registered migrations one version at a time
+// before they are decoded.
+type Schema struct {
+	Kind       string
+	Version    int
+	migrations map[int]Migration
+	newValue   func() any
+}
+
+type envelope struct {
+	Kind    string          `json:"kind"`
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// New returns the schema of kind at its current version. newValue returns
+// a pointer to an empty value of the type it decodes into.
+func New(kind string, version int, newValue func() any) *Schema {
+	return &Schema{Kind: kind, Version: version, migrations: map[int]Migration{}, newValue: newValue}
+}
+
+// Migrate registers the migration from version from to from+1.
+func (s *Schema) Migrate(from int, migration Migration) *Schema {
+	s.migrations[from] = migration
+	return s
+}
+
+func (s *Schema) Encode(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(envelope{Kind: s.Kind, Version: s.Version, Data: data}, "", "  ")
+}
+
+func (s *Schema) Decode(data []byte, v any) error {
+	data, err := s.upgrade(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// unwrap returns the version and data of a file. Files from before
+// snapshots existed have no envelope; their version, if they have one at
+// all, is a field of the data.
+func (s *Schema) unwrap(data []byte) (int, json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return 0, nil, err
+	}
+	if _, ok := fields["kind"]; ok {
+		var env envelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			return 0, nil, err
+		}
+		if env.Kind != s.Kind {
+			return 0, nil, fmt.Errorf("snapshot: file holds a %s, not a %s", env.Kind, s.Kind)
+		}
+		return env.Version, env.Data, nil
+	}
+
+	var version int
+	if raw, ok := fields["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return 0, nil, err
+		}
+		delete(fields, "version")
+	}
+	data, err := json.Marshal(fields)
+	return version, data, err
+}
+
+func (s *Schema) upgrade(data []byte) ([]byte, error) {
+	version, data, err := s.unwrap(data)
+	if err != nil {
+		return nil, err
+	}
+	if version > s.Version {
+		return nil, fmt.Errorf("%w: %s version %d, want at most %d", ErrTooNew, s.Kind, version, s.Version)
+	}
+	if version == s.Version {
+		return data, nil
+	}
+
+	var doc map[string]any
+	var decoder = json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	for ; version < s.Version; version++ {
+		var migration, ok = s.migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("snapshot: no migration for %s version %d", s.Kind, version)
+		}
+		if err := migration(doc); err != nil {
+			return nil, fmt.Errorf("snapshot: migrating %s version %d: %w", s.Kind, version, err)
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// Objects returns the JSON objects in the array v, for migrations that
+// change every element of a list.
+func Objects(v any) []map[string]any {
+	var array, _ = v.([]any)
+	var objects []map[string]any
+	for _, element := range array {
+		if object, ok := element.(map[string]any); ok {
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
@@ -0,0 +1,105 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type file struct {
+	Seed  int64  `json:"seed"`
+	Color string `json:"color"`
+	Lives int    `json:"lives"`
+}
+
+// The file gained lives in version 1 and renamed colour to color in
+// version 2.
+func newSchema() *Schema {
+	return New("test", 2, func() any { return &file{} }).
+		Migrate(0, func(doc map[string]any) error {
+			doc["lives"] = 3
+			return nil
+		}).
+		Migrate(1, func(doc map[string]any) error {
+			doc["color"] = doc["colour"]
+			delete(doc, "colour")
+			return nil
+		})
+}
+
+func TestDecode(t *testing.T) {
+	var want = file{Seed: 1<<62 + 1, Color: "red", Lives: 3}
+	var tests = []struct {
+		name string
+		data string
+	}{
+		{"no envelope", `{"seed": 4611686018427387905, "colour": "red"}`},
+		{"version field", `{"version": 1, "seed": 4611686018427387905, "colour": "red", "lives": 3}`},
+		{"old envelope", `{"kind": "test", "version": 1, "data": {"seed": 4611686018427387905, "colour": "red", "lives": 3}}`},
+		{"current envelope", `{"kind": "test", "version": 2, "data": {"seed": 4611686018427387905, "color": "red", "lives": 3}}`},
+	}
+	for _, test := range tests {
+		var got file
+		if err := newSchema().Decode([]byte(test.data), &got); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if got != want {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, want)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var got file
+	if err := newSchema().Decode([]byte(`{"kind": "test", "version": 3, "data": {}}`), &got); !errors.Is(err, ErrTooNew) {
+		t.Errorf("newer version: got %v, want ErrTooNew", err)
+	}
+	if err := newSchema().Decode([]byte(`{"kind": "other", "version": 2, "data": {}}`), &got); err == nil {
+		t.Error("other kind: got no error")
+	}
+	var missing = New("test", 2, func() any { return &file{} }).Migrate(1, func(map[string]any) error { return nil })
+	if err := missing.Decode([]byte(`{"seed": 1}`), &got); err == nil {
+		t.Error("missing migration: got no error")
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	var s = newSchema()
+	var want = file{Seed: 7, Color: "blue", Lives: 2}
+	data, err := s.Encode(&want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got file
+	if err := s.Decode(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	var dir = t.TempDir()
+	var s = newSchema()
+	if err := os.WriteFile(filepath.Join(dir, "test-v0.json"), []byte(`{"seed": 5, "colour": "green"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckGolden(dir); err == nil {
+		t.Error("CheckGolden passed without a golden file of the current version")
+	}
+	if err := s.UpdateGolden(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckGolden(dir); err != nil {
+		t.Error(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "test-v1.json"), []byte(`{"version": 1, "seed": 6, "colour": "green", "lives": 3}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckGolden(dir); err == nil {
+		t.Error("CheckGolden passed with an old golden file that decodes to another value")
+	}
+}
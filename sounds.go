@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/akshayxml/spaders/events"
+)
+
+// Sound effects are made when the game starts rather than loaded, as the
+// square waves and noise of the arcade machine.
+
+const (
+	soundVolume = 0.25
+	// The same sound started again this soon after, like the bursts when a
+	// spectator catches up, is skipped.
+	soundGap = 30 * time.Millisecond
+)
+
+// A tone sweeps from one pitch to another over its length, fading out.
+// Noise ignores the pitches.
+type tone struct {
+	from, to float64
+	seconds  float64
+	noise    bool
+}
+
+var (
+	enemyKilledSound     = tone{from: 880, to: 220, seconds: 0.12}
+	bunkerHitSound       = tone{seconds: 0.05, noise: true}
+	playerHitSound       = tone{seconds: 0.6, noise: true}
+	bulletsCollidedSound = tone{from: 1760, to: 1320, seconds: 0.06}
+	waveClearedSound     = tone{from: 440, to: 1760, seconds: 0.4}
+	gameOverSound        = tone{from: 440, to: 110, seconds: 0.8}
+)
+
+// samples renders t as 16-bit stereo at the sample rate of the audio
+// context.
+func (t tone) samples() []byte {
+	var count = int(t.seconds * sampleRate)
+	var data = make([]byte, 0, 4*count)
+	var rng = rand.New(rand.NewSource(1))
+	var phase, value = 0.0, 0.0
+	for i := 0; i < count; i++ {
+		var progress = float64(i) / float64(count)
+		if t.noise {
+			// A new random level every few samples sounds rougher than
+			// white noise, like the original's explosions.
+			if i%8 == 0 {
+				value = rng.Float64()*2 - 1
+			}
+		} else {
+			phase += (t.from + (t.to-t.from)*progress) / sampleRate
+			value = 1
+			if math.Mod(phase, 1) >= 0.5 {
+				value = -1
+			}
+		}
+		var sample = uint16(int16(value * (1 - progress) * math.MaxInt16))
+		data = binary.LittleEndian.AppendUint16(data, sample)
+		data = binary.LittleEndian.AppendUint16(data, sample)
+	}
+	return data
+}
+
+type sound struct {
+	data   []byte
+	played time.Time
+}
+
+func (s *sound) play() {
+	if audioContext == nil || time.Since(s.played) < soundGap {
+		return
+	}
+	s.played = time.Now()
+	var player = audioContext.NewPlayerFromBytes(s.data)
+	player.SetVolume(soundVolume)
+	player.Play()
+}
+
+// subscribeSounds plays a sound for each event unless muted says not to,
+// which keeps the attract mode quiet.
+func subscribeSounds(bus *events.Bus, muted func() bool) {
+	var on = func(t tone) func() {
+		var s = &sound{data: t.samples()}
+		return func() {
+			if !muted() {
+				s.play()
+			}
+		}
+	}
+	var enemyKilled, bunkerHit, playerHit = on(enemyKilledSound), on(bunkerHitSound), on(playerHitSound)
+	var bulletsCollided, waveCleared, gameOver = on(bulletsCollidedSound), on(waveClearedSound), on(gameOverSound)
+	events.Subscribe(bus, func(events.EnemyKilled) { enemyKilled() })
+	events.Subscribe(bus, func(events.BunkerHit) { bunkerHit() })
+	events.Subscribe(bus, func(events.PlayerHit) { playerHit() })
+	events.Subscribe(bus, func(events.BulletsCollided) { bulletsCollided() })
+	events.Subscribe(bus, func(events.WaveCleared) { waveCleared() })
+	events.Subscribe(bus, func(events.GameOver) { gameOver() })
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/akshayxml/spaders/input"
+	"github.com/akshayxml/spaders/models/GameMode"
+	"github.com/akshayxml/spaders/models/Screen"
+	"github.com/akshayxml/spaders/netplay"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/theme"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// spectateGame follows a game broadcast by another player. In versus, where
+// there is more than one world, the viewer picks which one to watch.
+type spectateGame struct {
+	spectator *netplay.Spectator
+	view      int
+}
+
+func (g *Game) startSpectating(addr string) error {
+	spectator, err := netplay.Spectate(addr)
+	if err != nil {
+		return err
+	}
+	g.spectate = &spectateGame{spectator: spectator}
+	spectator.OnStep = func(w *simulation.World) {
+		if w == g.spectatedWorld() {
+			g.events.PublishAll(w.Events)
+		}
+	}
+	g.screen = Screen.Spectate
+	return nil
+}
+
+func (g *Game) closeSpectate() {
+	if g.spectate != nil {
+		g.spectate.spectator.Close()
+		g.spectate = nil
+		g.mode = GameMode.Single
+	}
+}
+
+func (g *Game) updateSpectate() {
+	if g.actions.IsJustPressed(input.Back) {
+		g.reset()
+		return
+	}
+	var spectator = g.spectate.spectator
+	spectator.Update()
+	if len(spectator.Worlds) == 0 {
+		return
+	}
+
+	if g.actions.IsJustPressed(input.MoveLeft) || g.actions.IsJustPressed(input.MoveRight) {
+		g.spectate.view = (g.spectate.view + 1) % len(spectator.Worlds)
+	}
+	g.mode = spectator.Setup.Mode
+	g.turns = spectator.Worlds
+	g.world = g.spectatedWorld()
+	if g.mode == GameMode.Alternating {
+		g.currentTurn = spectator.Turn
+		g.keepEffects()
+	}
+}
+
+// spectatedWorld is the world on screen: the one being played in
+// alternating mode, or the one picked.
+func (g *Game) spectatedWorld() *simulation.World {
+	var spectator = g.spectate.spectator
+	if spectator.Setup.Mode == GameMode.Alternating {
+		return spectator.Worlds[spectator.Turn]
+	}
+	return spectator.Worlds[g.spectate.view%len(spectator.Worlds)]
+}
+
+func (g *Game) spectatedGameIsOver() bool {
+	for _, world := range g.spectate.spectator.Worlds {
+		if world.CanPlay() {
+			return false
+		}
+	}
+	return len(g.spectate.spectator.Worlds) > 0
+}
+
+// startBroadcast tells spectators, if the game is being broadcast, that a
+// game has started or been continued.
+func (g *Game) startBroadcast(setup simulation.Setup, worlds []*simulation.World, turn int) {
+	if g.broadcast != nil {
+		g.broadcast.Start(setup, worlds, turn)
+	}
+}
+
+func (g *Game) broadcastFrame(frame simulation.Frame) {
+	if g.broadcast != nil {
+		g.broadcast.Frame(frame)
+	}
+}
+
+// localFrame places the inputs of the world being played in a frame with
+// room for every turn.
+func (g *Game) localFrame(inputs []simulation.Input) simulation.Frame {
+	var frame = make(simulation.Frame, len(g.turns))
+	frame[g.currentTurn] = inputs
+	return frame
+}
+
+func (g *Game) DrawSpectateStatus(screen *ebiten.Image, palette theme.Palette) {
+	var face = &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   normalFontSize,
+	}
+	var spectator = g.spectate.spectator
+	if len(spectator.Worlds) > 1 && g.mode != GameMode.Alternating {
+		textOp := &text.DrawOptions{}
+		textOp.GeoM.Translate(250, 13)
+		textOp.ColorScale.ScaleWithColor(palette.Accent)
+		text.Draw(screen, "WATCHING P"+strconv.Itoa(g.spectate.view%len(spectator.Worlds)+1), face, textOp)
+	}
+
+	var msg string
+	if spectator.Err() != nil {
+		msg = "BROADCAST ENDED"
+	} else if len(spectator.Worlds) == 0 {
+		msg = "WAITING FOR GAME"
+	} else if g.spectatedGameIsOver() {
+		msg = "GAME OVER"
+	}
+	if msg != "" {
+		textOp := &text.DrawOptions{}
+		textOp.GeoM.Translate(float64(logicalWidth/2), float64(logicalHeight/2))
+		textOp.ColorScale.ScaleWithColor(palette.Accent)
+		textOp.PrimaryAlign = text.AlignCenter
+		text.Draw(screen, msg, face, textOp)
+	}
+}
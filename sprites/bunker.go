@@ -1,28 +1,24 @@
 package sprites
 
-import (
-	"github.com/akshayxml/spaders/models"
-	"image/color"
-)
+import "github.com/akshayxml/spaders/models"
 
 func GetBunkerRectangles() []models.Rectangle {
-	var neonGreen = color.RGBA{0x39, 0xFF, 0x14, 0xFF}
 	return []models.Rectangle{
-		{Position: models.Position{X: 0, Y: 8}, Width: 4, Height: 4, Color: neonGreen},
-		{Position: models.Position{X: 4, Y: 4}, Width: 4, Height: 8, Color: neonGreen},
-		{Position: models.Position{X: 8, Y: 4}, Width: 4, Height: 12, Color: neonGreen},
-		{Position: models.Position{X: 12, Y: 0}, Width: 4, Height: 16, Color: neonGreen},
-		{Position: models.Position{X: 16, Y: 0}, Width: 4, Height: 20, Color: neonGreen},
-		{Position: models.Position{X: 20, Y: 0}, Width: 4, Height: 24, Color: neonGreen},
-		{Position: models.Position{X: 24, Y: 0}, Width: 4, Height: 24, Color: neonGreen},
-		{Position: models.Position{X: 28, Y: 0}, Width: 4, Height: 20, Color: neonGreen},
-		{Position: models.Position{X: 32, Y: 0}, Width: 4, Height: 20, Color: neonGreen},
-		{Position: models.Position{X: 36, Y: 0}, Width: 4, Height: 24, Color: neonGreen},
-		{Position: models.Position{X: 40, Y: 0}, Width: 4, Height: 24, Color: neonGreen},
-		{Position: models.Position{X: 44, Y: 0}, Width: 4, Height: 20, Color: neonGreen},
-		{Position: models.Position{X: 48, Y: 0}, Width: 4, Height: 16, Color: neonGreen},
-		{Position: models.Position{X: 52, Y: 4}, Width: 4, Height: 12, Color: neonGreen},
-		{Position: models.Position{X: 56, Y: 4}, Width: 4, Height: 8, Color: neonGreen},
-		{Position: models.Position{X: 60, Y: 8}, Width: 4, Height: 4, Color: neonGreen},
+		{Position: models.Position{X: 0, Y: 8}, Width: 4, Height: 4},
+		{Position: models.Position{X: 4, Y: 4}, Width: 4, Height: 8},
+		{Position: models.Position{X: 8, Y: 4}, Width: 4, Height: 12},
+		{Position: models.Position{X: 12, Y: 0}, Width: 4, Height: 16},
+		{Position: models.Position{X: 16, Y: 0}, Width: 4, Height: 20},
+		{Position: models.Position{X: 20, Y: 0}, Width: 4, Height: 24},
+		{Position: models.Position{X: 24, Y: 0}, Width: 4, Height: 24},
+		{Position: models.Position{X: 28, Y: 0}, Width: 4, Height: 20},
+		{Position: models.Position{X: 32, Y: 0}, Width: 4, Height: 20},
+		{Position: models.Position{X: 36, Y: 0}, Width: 4, Height: 24},
+		{Position: models.Position{X: 40, Y: 0}, Width: 4, Height: 24},
+		{Position: models.Position{X: 44, Y: 0}, Width: 4, Height: 20},
+		{Position: models.Position{X: 48, Y: 0}, Width: 4, Height: 16},
+		{Position: models.Position{X: 52, Y: 4}, Width: 4, Height: 12},
+		{Position: models.Position{X: 56, Y: 4}, Width: 4, Height: 8},
+		{Position: models.Position{X: 60, Y: 8}, Width: 4, Height: 4},
 	}
 }
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/akshayxml/spaders/events"
+	"github.com/akshayxml/spaders/models/Screen"
+)
+
+// turnStats counts what was shot down during one turn of a game, for the
+// game over screen.
+type turnStats struct {
+	kills    int
+	shotDown int
+}
+
+// subscribeStats counts the events of the turn being played. The attract
+// demo and spectated games are left out.
+func (g *Game) subscribeStats() {
+	var current = func() *turnStats {
+		if g.screen != Screen.Play || g.currentTurn >= len(g.stats) {
+			return nil
+		}
+		return &g.stats[g.currentTurn]
+	}
+	events.Subscribe(g.events, func(events.EnemyKilled) {
+		if stats := current(); stats != nil {
+			stats.kills++
+		}
+	})
+	events.Subscribe(g.events, func(events.BulletsCollided) {
+		if stats := current(); stats != nil {
+			stats.shotDown++
+		}
+	})
+}
+
+func (s turnStats) String() string {
+	return "SHOT " + strconv.Itoa(s.kills) + " INVADERS AND " + strconv.Itoa(s.shotDown) + " BULLETS"
+}
@@ -0,0 +1,88 @@
+// Package theme holds the palettes the game can be drawn with.
+package theme
+
+import "image/color"
+
+type Palette struct {
+	Name string
+	// Titles, selected menu items and the numbers on the HUD.
+	Accent color.RGBA
+	// Labels and instructions.
+	Text         color.RGBA
+	Player       color.RGBA
+	Bunker       color.RGBA
+	PlayerBullet color.RGBA
+	EnemyBullet  color.RGBA
+	// The invader pictures are white and get tinted with Enemy.
+	Enemy color.RGBA
+	// The line along the bottom of the screen.
+	Ground color.RGBA
+	// What explosions and sparks fade to.
+	Explosion color.RGBA
+	Spark     color.RGBA
+	// Whether the starry background picture is drawn, rather than black.
+	Background bool
+	// Gels are laid over the finished picture.
+	Gels []Gel
+}
+
+// Gel is a strip of colored cellophane across the screen, from Top to
+// Bottom, like the ones glued onto the monitor of the original cabinet.
+// Whatever is drawn under it is multiplied by its color.
+type Gel struct {
+	Top, Bottom float64
+	Color       color.RGBA
+}
+
+var (
+	white     = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	neonGreen = color.RGBA{0x39, 0xFF, 0x14, 0xFF}
+)
+
+var Neon = Palette{
+	Name:         "NEON",
+	Accent:       neonGreen,
+	Text:         white,
+	Player:       neonGreen,
+	Bunker:       neonGreen,
+	PlayerBullet: white,
+	EnemyBullet:  white,
+	Enemy:        white,
+	Ground:       neonGreen,
+	Explosion:    color.RGBA{0xFF, 0x40, 0x40, 0xFF},
+	Spark:        color.RGBA{0xFF, 0xA0, 0x20, 0xFF},
+	Background:   true,
+}
+
+// Cabinet draws everything in white on black, the way the original monitor
+// did, and gets its colors from the gels: red near the top where the UFO
+// flies, and green near the bottom over the bunkers and the cannon.
+var Cabinet = Palette{
+	Name:         "CABINET",
+	Accent:       white,
+	Text:         white,
+	Player:       white,
+	Bunker:       white,
+	PlayerBullet: white,
+	EnemyBullet:  white,
+	Enemy:        white,
+	Ground:       white,
+	Explosion:    white,
+	Spark:        white,
+	Gels: []Gel{
+		{Top: 36, Bottom: 58, Color: color.RGBA{0xFF, 0x30, 0x30, 0xFF}},
+		{Top: 360, Bottom: 480, Color: neonGreen},
+	},
+}
+
+var Palettes = []Palette{Neon, Cabinet}
+
+// Find returns the palette called name, or Neon when there is none.
+func Find(name string) Palette {
+	for _, p := range Palettes {
+		if p.Name == name {
+			return p
+		}
+	}
+	return Neon
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/akshayxml/spaders/input"
+	"github.com/akshayxml/spaders/models"
+	"github.com/akshayxml/spaders/simulation"
+	"github.com/akshayxml/spaders/sprites"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Pointers held below this line steer the cannon; taps above it fire.
+const touchMoveZoneTop = logicalHeight - 100
+
+func menuItemY(i int) float64 {
+	return float64(logicalHeight/2) + 70 + float64(20*i)
+}
+
+func (g *Game) menuItemContains(i int, point input.Point) bool {
+	var width, height = text.Measure("->"+g.menuItemLabel(i), &text.GoTextFace{
+		Source: mplusFaceSource,
+		Size:   normalFontSize,
+	}, 0)
+	var left = float64(logicalWidth/2) - width/2
+	var top = menuItemY(i)
+	return point.X >= left && point.X <= left+width && point.Y >= top && point.Y <= top+height
+}
+
+func (g *Game) tappedMenuItem() (int, bool) {
+	for _, tap := range g.actions.Pointers().Taps() {
+		for i := 0; i < g.menuItemCount(); i++ {
+			if g.menuItemContains(i, tap) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (g *Game) isTapped() bool {
+	return len(g.actions.Pointers().Taps()) > 0
+}
+
+func (g *Game) isFireTapped() bool {
+	for _, tap := range g.actions.Pointers().Taps() {
+		if tap.Y < touchMoveZoneTop {
+			return true
+		}
+	}
+	return false
+}
+
+// pointerInput steers the cannon towards a pointer held in the movement zone
+// and fires on a tap above it.
+func (g *Game) pointerInput(player *models.Player) simulation.Input {
+	var in simulation.Input
+	if g.isFireTapped() {
+		in |= simulation.InputFire
+	}
+	var playerCenter = player.Position.X + simulation.GetSpritesWidth(sprites.GetPlayerRectangles())/2
+	for _, point := range g.actions.Pointers().Held() {
+		if point.Y < touchMoveZoneTop {
+			continue
+		}
+		if point.X < playerCenter-player.Speed {
+			in |= simulation.InputLeft
+		} else if point.X > playerCenter+player.Speed {
+			in |= simulation.InputRight
+		}
+		break
+	}
+	return in
+}
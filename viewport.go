@@ -0,0 +1,43 @@
+package main
+
+import (
+	"math"
+
+	"github.com/akshayxml/spaders/input"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// fitView places the logical screen in a window of width by height pixels,
+// as big as it fits and centered, with black bars on the sides that are
+// left over. Unless smooth, it is only ever scaled by whole numbers so that
+// every logical pixel is the same size.
+func fitView(width, height int, smooth bool) input.View {
+	var scale = min(float64(width)/logicalWidth, float64(height)/logicalHeight)
+	if !smooth && scale >= 1 {
+		scale = math.Floor(scale)
+	}
+	return input.View{
+		X:     math.Floor((float64(width) - logicalWidth*scale) / 2),
+		Y:     math.Floor((float64(height) - logicalHeight*scale) / 2),
+		Scale: scale,
+	}
+}
+
+// present draws the finished logical frame onto the screen where the view
+// puts it.
+func (g *Game) present(screen, frame *ebiten.Image) {
+	screen.Clear()
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(g.view.Scale, g.view.Scale)
+	opts.GeoM.Translate(g.view.X, g.view.Y)
+	if g.config.SmoothScaling {
+		opts.Filter = ebiten.FilterLinear
+	}
+	screen.DrawImage(frame, opts)
+}
+
+func (g *Game) toggleFullscreen() {
+	g.config.Fullscreen = !g.config.Fullscreen
+	ebiten.SetFullscreen(g.config.Fullscreen)
+	g.saveConfig()
+}